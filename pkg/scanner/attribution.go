@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The Sources used with the attribution flag, so it's clear which file or which part of a page needs to be fixed
+const (
+	pageBodySource = "page body"
+	//Followed by the number of the inline script, counting from 1 in the order they're on the page
+	inlineScriptSource = "inline script #"
+	//Followed by the URL of the script
+	externalScriptSource = "external: "
+	//For a finding in a page where it isn't known where in the page it is
+	unknownSource = "unknown"
+)
+
+// Matches a script tag and its content, up to the closing tag. The attributes are in the first group.
+var scriptTagPattern = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+
+// Matches a src attribute in the attributes of a script tag
+var scriptSrcPattern = regexp.MustCompile(`(?i)(^|\s)src\s*=`)
+
+// isJavaScript checks if a Content-Type header is for a script, ignoring any parameters like the charset
+func isJavaScript(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/javascript" || mediaType == "text/javascript"
+}
+
+// inlineScriptRanges gets where the content of each inline script is in a page, skipping scripts with a src attribute
+//
+// Parameters:
+//   - text: The HTML of the page.
+//
+// Returns:
+//   - [][2]int: The byte indexes of the start and end of the content of each inline script, in the order they're on the
+//     page.
+func inlineScriptRanges(text string) [][2]int {
+	var ranges [][2]int
+	for _, match := range scriptTagPattern.FindAllStringSubmatchIndex(text, -1) {
+		if scriptSrcPattern.MatchString(text[match[2]:match[3]]) {
+			continue
+		}
+		ranges = append(ranges, [2]int{match[4], match[5]})
+	}
+	return ranges
+}
+
+// A sourceAttribution works out the Source of findings in a searchContent with the attribution flag
+type sourceAttribution struct {
+	//Set with the attribution flag, otherwise every finding gets the source of the content
+	enabled bool
+	url     string
+	content searchContent
+	//The inline scripts in the content, if it's a page, see inlineScriptRanges
+	scripts [][2]int
+}
+
+// newSourceAttribution gets the sourceAttribution for the findings in a searchContent
+//
+// Parameters:
+//   - url: The URL that was searched.
+//   - content: The content the findings are in.
+//   - opts: The Options for the scan, without Attribution every finding keeps the source of the content.
+//
+// Returns:
+//   - sourceAttribution
+func newSourceAttribution(url string, content searchContent, opts Options) sourceAttribution {
+	attribution := sourceAttribution{enabled: opts.Attribution, url: url, content: content}
+	if opts.Attribution && content.page {
+		attribution.scripts = inlineScriptRanges(content.text)
+	}
+	return attribution
+}
+
+// source gets the Source of a finding that starts at a byte index in the content
+//
+// Findings in a page are "page body" or "inline script #N", and findings in a script are "external: " followed by its
+// URL, whether it was fetched with the page or queued and searched on its own. Pages are attributed by where the finding
+// is in the text that was searched, which for the DOM is the rendered HTML, so findings in it don't need a line and
+// column. Anything that isn't a page or a script keeps the source of the content.
+//
+// Parameters:
+//   - start: The byte index of the finding in the content, or -1 if it isn't known.
+//
+// Returns:
+//   - string: The Source for the finding, unknownSource if it's in a page but where isn't known.
+func (a sourceAttribution) source(start int) string {
+	switch {
+	case !a.enabled:
+		return a.content.source
+	case strings.HasPrefix(a.content.source, "script: "):
+		return externalScriptSource + strings.TrimPrefix(a.content.source, "script: ")
+	case a.content.source != "response" && a.content.source != "dom":
+		return a.content.source
+	case a.content.script:
+		return externalScriptSource + a.url
+	case a.content.page && start < 0:
+		return unknownSource
+	case a.content.page:
+		for n, script := range a.scripts {
+			if start >= script[0] && start < script[1] {
+				return fmt.Sprintf("%s%d", inlineScriptSource, n+1)
+			}
+		}
+		return pageBodySource
+	}
+	return a.content.source
+}
+
+// inURL checks if the line and column of a finding are a position in its URL, rather than in a sourcemap file or a
+// script fetched with the page
+func inURL(finding Finding) bool {
+	switch {
+	case finding.Source == "response" || finding.Source == "dom" || finding.Source == pageBodySource || finding.Source == unknownSource:
+		return true
+	case strings.HasPrefix(finding.Source, inlineScriptSource):
+		return true
+	}
+	return finding.Source == externalScriptSource+finding.URL
+}
@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// The contents of a baseline file
+type baselineFile struct {
+	Findings []Finding `json:"findings"`
+}
+
+// configureBaseline loads the baseline file from the Options, if it exists yet
+//
+// Returns:
+//   - error: If the file exists but can't be read or parsed, or the baseline-urls flag is used without a baseline.
+func (s *scanner) configureBaseline(o Options) error {
+	s.baselinePath = o.Baseline
+	s.baselineByURL = o.BaselineURLs
+	s.baselineFindings = nil
+	s.baselineKeys = nil
+	if s.baselinePath == "" {
+		if s.baselineByURL {
+			return fmt.Errorf("the baseline-urls flag needs a baseline file from the baseline flag")
+		}
+		return nil
+	}
+
+	var err error
+	s.baselineFindings, err = loadBaseline(s.baselinePath)
+	if err != nil {
+		return fmt.Errorf("failed to load baseline file %s: %w", s.baselinePath, err)
+	}
+	s.baselineKeys = map[string]struct{}{}
+	for _, finding := range s.baselineFindings {
+		s.baselineKeys[s.baselineKey(finding)] = struct{}{}
+	}
+	return nil
+}
+
+// loadBaseline reads the findings from a baseline file
+//
+// Parameters:
+//   - path: The path to the baseline file, which doesn't need to exist yet.
+//
+// Returns:
+//   - []Finding: The findings in the file, or nil if it doesn't exist.
+//   - error: If the file exists but can't be read or parsed.
+func loadBaseline(path string) ([]Finding, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline baselineFile
+	err = json.Unmarshal(data, &baseline)
+	if err != nil {
+		return nil, err
+	}
+	return baseline.Findings, nil
+}
+
+// baselineKey identifies a finding in the baseline by its type and value like findingKey, along with the URL if
+// baselineByURL is set
+func (s *scanner) baselineKey(finding Finding) string {
+	if s.baselineByURL {
+		return findingKey(finding) + "\x00" + finding.URL
+	}
+	return findingKey(finding)
+}
+
+// inBaseline checks if a finding was already reported by a run with the same baseline file
+func (s *scanner) inBaseline(finding Finding) bool {
+	if len(s.baselineKeys) == 0 {
+		return false
+	}
+	_, ok := s.baselineKeys[s.baselineKey(finding)]
+	return ok
+}
+
+// writeBaseline adds the new findings from a run to the baseline file, keeping the ones that were already in it so a
+// run that's stopped early or doesn't search every URL doesn't drop them
+//
+// Parameters:
+//   - findings: The findings reported by the run, which aren't in the baseline already.
+//
+// Returns:
+//   - error: If the file can't be written.
+func (s *scanner) writeBaseline(findings []Finding) error {
+	baseline := baselineFile{Findings: make([]Finding, 0, len(s.baselineFindings)+len(findings))}
+	baseline.Findings = append(baseline.Findings, s.baselineFindings...)
+	baseline.Findings = append(baseline.Findings, findings...)
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.baselinePath, data)
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"hash/crc32"
+	"strings"
+)
+
+// Added to the Details of matches that fail their checksum, which are only kept with the extra-patterns or
+// noisy flag
+const invalidChecksum = "invalid checksum"
+
+// The alphabet the checksums of GitHub and npm tokens are encoded with
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// checksumValidators checks the matches for patterns of tokens that have a checksum in them, keyed by the secret
+// description. Random strings that happen to have the right prefix and length almost never have a valid checksum.
+var checksumValidators = map[string]func(string) bool{
+	"GitHub Personal Access Token (Classic)": validTokenChecksum,
+	"GitHub OAuth 2.0 Access Token":          validTokenChecksum,
+	"GitHub User-to-Server Access Token":     validTokenChecksum,
+	"GitHub Server-to-Server Access Token":   validTokenChecksum,
+	"GitHub Refresh Token":                   validTokenChecksum,
+	"npm Access Token":                       validTokenChecksum,
+}
+
+// validTokenChecksum checks the checksum of a GitHub or npm token, like ghp_ or npm_ followed by 36 characters
+//
+// The last 6 characters are the CRC32 of the 30 random characters before them, base62 encoded and padded with zeros.
+//
+// Parameters:
+//   - token: The token, with its prefix.
+//
+// Returns:
+//   - bool: True if the checksum matches.
+func validTokenChecksum(token string) bool {
+	separator := strings.Index(token, "_")
+	if separator == -1 || len(token)-separator-1 != 36 {
+		return false
+	}
+	random := token[separator+1 : len(token)-6]
+	return token[len(token)-6:] == base62Checksum(random)
+}
+
+// base62Checksum gets the CRC32 of text, base62 encoded and padded with zeros to 6 characters
+func base62Checksum(text string) string {
+	checksum := crc32.ChecksumIEEE([]byte(text))
+	encoded := []byte{}
+	for checksum > 0 {
+		encoded = append([]byte{base62Alphabet[checksum%62]}, encoded...)
+		checksum /= 62
+	}
+	return strings.Repeat("0", 6-len(encoded)) + string(encoded)
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// ANSI escape codes for the colored text output
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// isTerminal checks if a writer is a terminal rather than a file or a pipe, like stdinIsPiped does for stdin
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps text in an ANSI color code if colorOutput is set, and returns it as is otherwise
+func (s *scanner) colorize(code string, text string) string {
+	if !s.colorOutput || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// writeTextFindings writes findings in the text format, one per line
+//
+// With colorOutput, the findings from every URL are grouped by type under a colored header, so the type isn't repeated
+// on each line, and each one has its location since it's no longer written under the URL it was found in.
+//
+// Parameters:
+//   - w: The writer to write the findings to.
+//   - findings: The findings to write.
+//   - opts: The Options for the scan, passed on to formatFinding.
+func (s *scanner) writeTextFindings(w io.Writer, findings []Finding, opts Options) {
+	if !s.colorOutput {
+		for _, finding := range findings {
+			fmt.Fprintln(w, s.formatFinding(finding, opts))
+		}
+		return
+	}
+
+	opts.Location = true
+	grouped := append([]Finding(nil), findings...)
+	//Stable so findings of the same type stay in the order they were found in
+	sort.SliceStable(grouped, func(i, j int) bool { return grouped[i].Type < grouped[j].Type })
+	for i, finding := range grouped {
+		if i == 0 || finding.Type != grouped[i-1].Type {
+			fmt.Fprintln(w, s.colorize(ansiBold+ansiCyan, finding.Type))
+		}
+		fmt.Fprintln(w, "  "+s.formatFinding(finding, opts))
+	}
+}
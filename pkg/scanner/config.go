@@ -0,0 +1,201 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	netUrl "net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// The Type used to report values in .env and JSON config files that are assigned to a key that looks like it's for a
+// secret, like DATABASE_PASSWORD, when they don't match any of the secret patterns
+const configSecretFinding = "Sensitive Config Value"
+
+// Matches a line of a .env file, like DATABASE_PASSWORD=hunter2 or export API_KEY="abc"
+var dotenvPattern = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*=\s*(.*?)\s*$`)
+
+// Matches the names of keys that are usually assigned secrets, once they're split into words by isSensitiveKey. The
+// words have to be whole, so tokenizer or bypass don't count. JSON keys are matched with their whole path, so
+// ConnectionStrings.DefaultConnection counts.
+var sensitiveKeyPattern = regexp.MustCompile(`(^|[^a-z0-9])(pass(words?|wd)?|secrets?|tokens?|api[_-]?keys?|private[_-]?keys?|access[_-]?keys?|credentials?|connection[_-]?strings?|dsn)($|[^a-z0-9])|(^|[_.-])key$`)
+
+// Matches where a new word starts in a camelCase key, like dbPassword or APIKey
+var camelCasePattern = regexp.MustCompile(`([a-z0-9])([A-Z])|([A-Z])([A-Z][a-z])`)
+
+// The names of JSON files that are usually config, which are parsed as config whatever their Content-Type is. Files
+// like appsettings.Production.json or config.local.json are matched by the part before the first dot.
+var jsonConfigNames = map[string]struct{}{
+	"appsettings":     {},
+	"auth":            {},
+	"config":          {},
+	"credentials":     {},
+	"database":        {},
+	"env":             {},
+	"environment":     {},
+	"firebase":        {},
+	"keys":            {},
+	"parameters":      {},
+	"secrets":         {},
+	"service-account": {},
+	"settings":        {},
+}
+
+// A configEntry is a value from a .env or JSON config file, along with the key it was assigned to
+type configEntry struct {
+	//The name of the variable in a .env file, or the path to the value in a JSON file like database.users[0].password
+	Key   string
+	Value string
+}
+
+// configFileName gets the file name from the path of a URL, lowercased
+func configFileName(url string) string {
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(path.Base(parsedUrl.Path))
+}
+
+// isDotenv checks if a URL is for a .env file, like /.env, /.env.production or /config/app.env
+func isDotenv(url string) bool {
+	name := configFileName(url)
+	return name == ".env" || strings.HasPrefix(name, ".env.") || strings.HasSuffix(name, ".env")
+}
+
+// isJSONConfig checks if a URL is for a JSON config file, like /config.json or /appsettings.Production.json. Other
+// JSON files like API responses are searched by their Content-Type instead.
+func isJSONConfig(url string) bool {
+	name := configFileName(url)
+	if !strings.HasSuffix(name, ".json") {
+		return false
+	}
+	_, ok := jsonConfigNames[strings.SplitN(name, ".", 2)[0]]
+	return ok
+}
+
+// isSensitiveKey checks if a key from a config file looks like it's assigned a secret, see sensitiveKeyPattern
+func isSensitiveKey(key string) bool {
+	return sensitiveKeyPattern.MatchString(strings.ToLower(camelCasePattern.ReplaceAllString(key, "${1}${3}_${2}${4}")))
+}
+
+// isConfigFile checks if a URL is for a .env or JSON config file, which are searched whatever their Content-Type is
+// since servers that expose them by accident usually send them as text/plain or application/octet-stream
+func isConfigFile(url string) bool {
+	return isDotenv(url) || isJSONConfig(url)
+}
+
+// getDotenvEntries gets the KEY=VALUE pairs from a .env file
+//
+// Comments and blank lines are skipped, and so are empty values. Values in quotes have the quotes removed, and
+// unquoted values have any comment after them removed.
+//
+// Parameters:
+//   - text: The .env file.
+//
+// Returns:
+//   - []configEntry: The entries in the order they're in the file.
+func getDotenvEntries(text string) []configEntry {
+	var entries []configEntry
+	for _, line := range strings.Split(text, "\n") {
+		match := dotenvPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		value := match[2]
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && strings.IndexByte(value[1:], value[0]) > 0 {
+			value = value[1 : 1+strings.IndexByte(value[1:], value[0])]
+		} else if comment := strings.Index(value, " #"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+		if value == "" {
+			continue
+		}
+		entries = append(entries, configEntry{Key: match[1], Value: value})
+	}
+	return entries
+}
+
+// getJSONEntries gets the string values from a JSON config file, along with the path to each one
+//
+// Parameters:
+//   - text: The JSON to parse.
+//
+// Returns:
+//   - []configEntry: The string values in the order they're in the JSON, with keys like database.users[0].password.
+//   - error: If the text isn't valid JSON.
+func getJSONEntries(text string) ([]configEntry, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	var entries []configEntry
+	err := readJSONEntries(decoder, "", &entries)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON, more than one value")
+	}
+	return entries, nil
+}
+
+// readJSONEntries reads the next value from the decoder for getJSONEntries, adding the strings in it to the entries
+//
+// Parameters:
+//   - decoder: The decoder to read from.
+//   - key: The path to the value.
+//   - entries: The entries to add to.
+//
+// Returns:
+//   - error: If the JSON is invalid, io.EOF if it ends before the value is finished.
+func readJSONEntries(decoder *json.Decoder, key string, entries *[]configEntry) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch value := token.(type) {
+	case string:
+		*entries = append(*entries, configEntry{Key: key, Value: value})
+	case json.Delim:
+		for i := 0; decoder.More(); i++ {
+			child := fmt.Sprintf("%s[%d]", key, i)
+			if value == '{' {
+				token, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				child, _ = token.(string)
+				if key != "" {
+					child = key + "." + child
+				}
+			}
+			err := readJSONEntries(decoder, child, entries)
+			if err != nil {
+				return err
+			}
+		}
+		//The closing } or ]
+		_, err = decoder.Token()
+		return err
+	}
+	return nil
+}
+
+// entryKey finds the key a secret was assigned to in a config file
+//
+// Parameters:
+//   - entries: The entries from the config file.
+//   - value: The secret, which can be the whole value or part of it like the password in a connection string.
+//
+// Returns:
+//   - string: The key of the first entry with the secret in it, or empty if it isn't in any of them.
+func entryKey(entries []configEntry, value string) string {
+	for _, entry := range entries {
+		if strings.Contains(entry.Value, value) {
+			return entry.Key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	netUrl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// parseCookies parses the values passed to the cookie flag
+//
+// Each value can be a single name=value pair, or several separated by semicolons like in a Cookie header.
+//
+// Parameters:
+//   - values: A slice of strings containing the cookies.
+//
+// Returns:
+//   - []*http.Cookie: The parsed cookies.
+//   - error
+func parseCookies(values []string) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			name, cookieValue, found := strings.Cut(pair, "=")
+			name = strings.TrimSpace(name)
+			if !found || name == "" {
+				return nil, fmt.Errorf("invalid cookie %s: should be in the format \"name=value\"", pair)
+			}
+			cookies = append(cookies, &http.Cookie{Name: name, Value: strings.TrimSpace(cookieValue)})
+		}
+	}
+	return cookies, nil
+}
+
+// loadCookieFile reads a cookie file in the Netscape cookies.txt format, which most browser extensions can export
+//
+// Each line has the domain, whether subdomains are included, the path, whether the cookie is secure, the expiry as a
+// unix timestamp, the name and the value, separated by tabs. Blank lines and comment lines starting with # are skipped,
+// except for the #HttpOnly_ prefix on the domain of HttpOnly cookies.
+//
+// Parameters:
+//   - path: The path to the file.
+//
+// Returns:
+//   - []*http.Cookie: The cookies in the file, with their Domain set.
+//   - error
+func loadCookieFile(path string) ([]*http.Cookie, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cookies []*http.Cookie
+	for i, line := range strings.Split(string(file), "\n") {
+		line = strings.TrimRight(line, "\r")
+		httpOnly := strings.HasPrefix(line, "#HttpOnly_")
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookie file %s: line %d should have 7 tab separated fields", path, i+1)
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie file %s: line %d has an invalid expiry: %w", path, i+1, err)
+		}
+
+		cookie := &http.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		//An expiry of 0 is a session cookie
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, nil
+}
+
+// newCookieJar creates the cookie jar for httpClient, with the cookies from a cookie file already in it
+//
+// The jar also keeps any cookies set by the sites that are searched, so a session started by one page is used for the rest.
+//
+// Parameters:
+//   - cookies: The cookies to add to the jar, which need their Domain set.
+//
+// Returns:
+//   - http.CookieJar
+//   - error
+func newCookieJar(cookies []*http.Cookie) (http.CookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, cookie := range cookies {
+		host := strings.TrimPrefix(cookie.Domain, ".")
+		//Domains without a leading dot are only for that host, not its subdomains, which the jar needs an empty Domain for
+		if !strings.HasPrefix(cookie.Domain, ".") {
+			hostOnly := *cookie
+			hostOnly.Domain = ""
+			cookie = &hostOnly
+		}
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		jar.SetCookies(&netUrl.URL{Scheme: scheme, Host: host, Path: path}, []*http.Cookie{cookie})
+	}
+	return jar, nil
+}
+
+// cookieParams converts cookies for network.SetCookies, so the headless browser in getDOM sends them too
+//
+// Parameters:
+//   - cookies: The cookies to convert.
+//   - url: The URL to set cookies without a Domain for, they are left out if it is out of scope.
+//
+// Returns:
+//   - []*network.CookieParam
+func (s *scanner) cookieParams(cookies []*http.Cookie, url string) []*network.CookieParam {
+	var params []*network.CookieParam
+	for _, cookie := range cookies {
+		//Cookies without a Domain are only sent to hosts in scope, the same as in getContents
+		if cookie.Domain == "" && !s.inScope(url) {
+			continue
+		}
+		param := &network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+		}
+		if cookie.Domain != "" {
+			param.Domain = cookie.Domain
+		} else {
+			param.URL = url
+		}
+		if !cookie.Expires.IsZero() {
+			expires := cdp.TimeSinceEpoch(cookie.Expires)
+			param.Expires = &expires
+		}
+		params = append(params, param)
+	}
+	return params
+}
@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	netUrl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCookies(t *testing.T) {
+	//Test case: Single cookies and Cookie header style values
+	cookies, err := parseCookies([]string{"session=abc123", "theme=dark; lang = en;"})
+	assert.Nil(t, err, "Unexpected error")
+	if assert.Len(t, cookies, 3, "Unexpected number of cookies") {
+		assert.Equal(t, "session", cookies[0].Name, "Unexpected cookie name")
+		assert.Equal(t, "abc123", cookies[0].Value, "Unexpected cookie value")
+		assert.Equal(t, "lang", cookies[2].Name, "Expected whitespace to be trimmed")
+		assert.Equal(t, "en", cookies[2].Value, "Expected whitespace to be trimmed")
+	}
+
+	//Test case: Invalid cookies
+	_, err = parseCookies([]string{"session"})
+	assert.NotNil(t, err, "Expected error for cookie without a value")
+	_, err = parseCookies([]string{"=abc123"})
+	assert.NotNil(t, err, "Expected error for cookie without a name")
+}
+
+func TestLoadCookieFile(t *testing.T) {
+	dir := t.TempDir()
+
+	//Test case: Netscape format, with comments, HttpOnly cookies and session cookies
+	path := filepath.Join(dir, "cookies.txt")
+	cookieFile := "# Netscape HTTP Cookie File\r\n\r\n" +
+		".example.com\tTRUE\t/\tTRUE\t2000000000\tsession\tabc123\r\n" +
+		"#HttpOnly_app.example.com\tFALSE\t/admin\tFALSE\t0\ttoken\txyz\r\n"
+	err := os.WriteFile(path, []byte(cookieFile), 0644)
+	assert.Nil(t, err, "Unexpected error writing cookie file")
+	cookies, err := loadCookieFile(path)
+	assert.Nil(t, err, "Unexpected error loading cookie file")
+	expected := []*http.Cookie{
+		{Domain: ".example.com", Path: "/", Secure: true, Name: "session", Value: "abc123", Expires: time.Unix(2000000000, 0)},
+		{Domain: "app.example.com", Path: "/admin", Name: "token", Value: "xyz", HttpOnly: true},
+	}
+	assert.Equal(t, expected, cookies, "Unexpected cookies")
+
+	//Test case: Invalid lines
+	err = os.WriteFile(path, []byte("example.com\tTRUE\t/\n"), 0644)
+	assert.Nil(t, err, "Unexpected error writing cookie file")
+	_, err = loadCookieFile(path)
+	assert.NotNil(t, err, "Expected error for a line with missing fields")
+
+	//Test case: Missing file
+	_, err = loadCookieFile(filepath.Join(dir, "missing.txt"))
+	assert.NotNil(t, err, "Expected error for missing cookie file")
+}
+
+func TestNewCookieJar(t *testing.T) {
+	cookies := []*http.Cookie{
+		{Domain: ".example.com", Path: "/", Name: "session", Value: "abc123"},
+		{Domain: "app.example.com", Path: "/", Name: "token", Value: "xyz"},
+	}
+	jar, err := newCookieJar(cookies)
+	assert.Nil(t, err, "Unexpected error")
+
+	//Test case: Domain cookies are sent to subdomains, host only cookies are not
+	url, _ := netUrl.Parse("https://www.example.com/app.js")
+	assert.Len(t, jar.Cookies(url), 1, "Expected only the domain cookie for a subdomain")
+	url, _ = netUrl.Parse("https://app.example.com/app.js")
+	assert.Len(t, jar.Cookies(url), 2, "Expected both cookies for the host")
+	url, _ = netUrl.Parse("https://example.org/")
+	assert.Empty(t, jar.Cookies(url), "Expected no cookies for other domains")
+}
+
+func TestCookieParams(t *testing.T) {
+	s := newScanner()
+	cookies := []*http.Cookie{
+		{Name: "session", Value: "abc123"},
+		{Domain: ".example.com", Path: "/", Name: "token", Value: "xyz", Secure: true, Expires: time.Unix(2000000000, 0)},
+	}
+
+	//Test case: Cookies without a Domain are set for the page URL
+	params := s.cookieParams(cookies, "https://example.com/")
+	if assert.Len(t, params, 2, "Unexpected number of cookies") {
+		assert.Equal(t, "https://example.com/", params[0].URL, "Expected the page URL")
+		assert.Empty(t, params[0].Domain, "Expected no domain")
+		assert.Equal(t, ".example.com", params[1].Domain, "Expected the cookie domain")
+		assert.True(t, params[1].Secure, "Expected a secure cookie")
+		if assert.NotNil(t, params[1].Expires, "Expected an expiry") {
+			assert.Equal(t, int64(2000000000), params[1].Expires.Time().Unix(), "Unexpected expiry")
+		}
+	}
+
+	//Test case: Cookies without a Domain aren't set for pages out of scope
+	s.crawlScope = []string{"example.org"}
+	params = s.cookieParams(cookies, "https://example.com/")
+	if assert.Len(t, params, 1, "Expected only the cookie with a Domain") {
+		assert.Equal(t, "token", params[0].Name, "Unexpected cookie")
+	}
+}
+
+func TestGetContentsCookies(t *testing.T) {
+	s := newScanner()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			//localhost is the same server, but a different host to the 127.0.0.1 in the URL
+			http.Redirect(w, r, strings.Replace(r.Header.Get("X-Redirect"), "127.0.0.1", "localhost", 1), http.StatusFound)
+			return
+		}
+		for _, cookie := range r.Cookies() {
+			fmt.Fprintf(w, "%s=%s;", cookie.Name, cookie.Value)
+		}
+		fmt.Fprintf(w, "X-API-Key=%s;", r.Header.Get("X-API-Key"))
+	}))
+	defer mockServer.Close()
+
+	//Test case: Cookies from the cookie flag and the cookie jar are both sent
+	s.requestCookies = []*http.Cookie{{Name: "session", Value: "abc123"}}
+	jar, err := newCookieJar([]*http.Cookie{{Domain: "127.0.0.1", Path: "/", Name: "token", Value: "xyz"}})
+	assert.Nil(t, err, "Unexpected error")
+	s.httpClient = &http.Client{Timeout: time.Second, Jar: jar}
+	result, _, err := s.getContents(context.TODO(), mockServer.URL, mockServer.URL)
+	assert.Nil(t, err, "Unexpected error")
+	if assert.NotNil(t, result, "Expected content") {
+		assert.Contains(t, *result, "session=abc123;", "Expected the cookie from the cookie flag")
+		assert.Contains(t, *result, "token=xyz;", "Expected the cookie from the cookie jar")
+	}
+
+	//Test case: The cookie and header flags are only sent to hosts in scope
+	s.requestHeaders = http.Header{"X-Api-Key": {"secret"}}
+	s.crawlScope = []string{"example.com"}
+	result, _, err = s.getContents(context.TODO(), mockServer.URL, mockServer.URL)
+	assert.Nil(t, err, "Unexpected error")
+	if assert.NotNil(t, result, "Expected content") {
+		assert.Equal(t, "token=xyz;X-API-Key=;", *result, "Expected only the cookie from the cookie jar")
+	}
+
+	//Test case: The header flags aren't sent on to a redirect out of scope
+	s.httpClient.CheckRedirect = s.redirectPolicy(true, 10)
+	s.crawlScope = []string{"127.0.0.1"}
+	s.requestHeaders.Set("X-Redirect", mockServer.URL+"/")
+	result, _, err = s.getContents(context.TODO(), mockServer.URL+"/redirect", mockServer.URL)
+	assert.Nil(t, err, "Unexpected error")
+	if assert.NotNil(t, result, "Expected content") {
+		assert.Equal(t, "X-API-Key=;", *result, "Expected no header or cookies for the redirect")
+	}
+}
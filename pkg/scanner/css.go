@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	netUrl "net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// The Type used to report the url() and @import targets found in stylesheets with the urls flag
+const cssURLFinding = "CSS URL"
+
+// Matches url() with a double quoted, single quoted or unquoted target, and @import with a quoted target. @import url()
+// is matched by the url() pattern.
+var cssURLPattern = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^"'()\s]*))\s*\)`)
+var cssImportPattern = regexp.MustCompile(`(?i)@import\s+(?:"([^"]*)"|'([^']*)')`)
+
+// Extensions of the images, fonts and media that stylesheets load, which aren't worth searching or reporting
+var cssAssetExtensions = map[string]struct{}{
+	".png": {}, ".jpg": {}, ".jpeg": {}, ".gif": {}, ".webp": {}, ".avif": {}, ".bmp": {}, ".ico": {}, ".cur": {}, ".svg": {},
+	".woff": {}, ".woff2": {}, ".ttf": {}, ".otf": {}, ".eot": {},
+	".mp3": {}, ".mp4": {}, ".webm": {}, ".ogg": {},
+}
+
+// isCSS checks if a Content-Type header is for a stylesheet, ignoring any parameters like the charset
+func isCSS(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), "text/css")
+}
+
+// getCSSURLs gets the url() and @import targets from a stylesheet
+//
+// Inline data: URLs and fragments like url(#gradient) don't point anywhere, so they are skipped.
+//
+// Parameters:
+//   - text: The stylesheet to search.
+//
+// Returns:
+//   - []string: The targets as they are written in the stylesheet, in the order they were first found.
+func getCSSURLs(text string) []string {
+	var targets []string
+	seen := map[string]struct{}{}
+	for _, pattern := range []*regexp.Regexp{cssImportPattern, cssURLPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+			//Only one of the groups is set, depending on how the target was quoted
+			target := strings.TrimSpace(strings.Join(match[1:], ""))
+			if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(strings.ToLower(target), "data:") {
+				continue
+			}
+			if _, ok := seen[target]; ok {
+				continue
+			}
+			seen[target] = struct{}{}
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// isCSSAsset checks if a URL from a stylesheet is an image, font or media file, based on the extension of its path
+func isCSSAsset(url string) bool {
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return false
+	}
+	_, ok := cssAssetExtensions[strings.ToLower(path.Ext(parsedUrl.Path))]
+	return ok
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testStylesheet = `@import "theme.css";
+@import url('https://cdn.example.net/fonts.css');
+.logo { background: url(/images/logo.png) no-repeat; }
+.hero { background-image: url("/internal/render?id=1"); }
+.icon { background: url(data:image/png;base64,iVBORw0KGgo=); fill: url(#gradient); }
+.logo-small { background: url(/images/logo.png); }
+`
+
+func TestIsCSS(t *testing.T) {
+	assert.True(t, isCSS("text/css"), "Expected text/css")
+	assert.True(t, isCSS("Text/CSS; charset=utf-8"), "Expected parameters and case to be ignored")
+	assert.False(t, isCSS("text/html"), "Expected text/html not to be CSS")
+}
+
+func TestGetCSSURLs(t *testing.T) {
+	//Test case: @import and url() targets, skipping data: URLs, fragments and duplicates
+	expected := []string{"theme.css", "https://cdn.example.net/fonts.css", "/images/logo.png", "/internal/render?id=1"}
+	assert.Equal(t, expected, getCSSURLs(testStylesheet), "Unexpected stylesheet URLs")
+}
+
+func TestIsCSSAsset(t *testing.T) {
+	assert.True(t, isCSSAsset("https://example.com/images/logo.PNG?v=2"), "Expected images to be assets")
+	assert.True(t, isCSSAsset("https://example.com/fonts/main.woff2#iefix"), "Expected fonts to be assets")
+	assert.False(t, isCSSAsset("https://example.com/theme.css"), "Expected stylesheets not to be assets")
+	assert.False(t, isCSSAsset("https://example.com/internal/render?id=1"), "Expected endpoints not to be assets")
+}
+
+func TestSearchCSS(t *testing.T) {
+	s := newScanner()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		fmt.Fprint(w, testStylesheet)
+	}))
+	defer mockServer.Close()
+	s.crawlScope = seedScope([]string{mockServer.URL})
+	s.output = io.Discard
+
+	//Test case: In-scope targets that aren't images or fonts are queued and reported - This will print a Warning, but pass
+	opts := Options{Secrets: true, URLs: true, IgnoreRobots: true}
+	s.compiledSecretRegex = s.compileSecretRegex(opts)
+	urlQueue := &URLQueue{}
+	out, err := s.search(context.TODO(), mockServer.URL+"/style.css", 0, opts, urlQueue)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{mockServer.URL + "/theme.css", mockServer.URL + "/internal/render?id=1"}, urlQueue.queue, "Unexpected queued URLs")
+	var cssUrls []string
+	for _, finding := range out {
+		if finding.Type == cssURLFinding {
+			cssUrls = append(cssUrls, finding.Value)
+		}
+	}
+	expected := []string{mockServer.URL + "/theme.css", "https://cdn.example.net/fonts.css", mockServer.URL + "/internal/render?id=1"}
+	assert.Equal(t, expected, cssUrls, "Unexpected stylesheet URL findings")
+}
@@ -0,0 +1,176 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// How often networkTracker.wait checks if the network is idle
+const networkIdlePoll = 50 * time.Millisecond
+
+// A networkTracker follows the requests a browser tab makes, so getDOM can wait for a single page app to finish
+// loading its data and scripts before searching the DOM, and report the XHR and fetch requests it made
+type networkTracker struct {
+	mu       sync.Mutex
+	inFlight map[network.RequestID]struct{}
+	//When a request last started or finished
+	lastActivity time.Time
+	//The XHR and fetch requests, in the order they were sent, see endpoints
+	requests []xhrRequest
+	seen     map[xhrRequest]struct{}
+	//The request for the page itself and the headers of its response, see pageHeader
+	documentID     network.RequestID
+	documentHeader http.Header
+}
+
+// newNetworkTracker creates a networkTracker, which needs to be listening to a tab before it navigates
+//
+// Example:
+//
+//	tracker := newNetworkTracker()
+//	chromedp.ListenTarget(ctx, tracker.handle)
+func newNetworkTracker() *networkTracker {
+	return &networkTracker{inFlight: map[network.RequestID]struct{}{}, lastActivity: time.Now(), seen: map[xhrRequest]struct{}{}}
+}
+
+// handle updates the requests in flight from the network events of a tab
+func (n *networkTracker) handle(event interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch event := event.(type) {
+	case *network.EventRequestWillBeSent:
+		n.inFlight[event.RequestID] = struct{}{}
+		n.addRequest(event)
+		//The first document is the page, iframes come after it and redirects keep the same RequestID
+		if n.documentID == "" && event.Type == network.ResourceTypeDocument {
+			n.documentID = event.RequestID
+		}
+	case *network.EventLoadingFinished:
+		delete(n.inFlight, event.RequestID)
+	case *network.EventLoadingFailed:
+		delete(n.inFlight, event.RequestID)
+	case *network.EventResponseReceived:
+		//Not activity, the request is still in flight until it finishes loading
+		if event.RequestID == n.documentID && event.Response != nil {
+			n.documentHeader = responseHeader(event.Response.Headers)
+		}
+		return
+	default:
+		return
+	}
+	n.lastActivity = time.Now()
+}
+
+// pageHeader gets the response headers of the page the tab navigated to, or nil if there wasn't a response for it
+func (n *networkTracker) pageHeader() http.Header {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.documentHeader
+}
+
+// responseHeader converts the headers of a response from the browser to an http.Header. The browser joins the values of
+// a header that was sent more than once with newlines, so they're split back up.
+func responseHeader(headers network.Headers) http.Header {
+	header := http.Header{}
+	for name, value := range headers {
+		for _, line := range strings.Split(fmt.Sprint(value), "\n") {
+			header.Add(name, line)
+		}
+	}
+	return header
+}
+
+// idle checks if no requests are in flight, and none have started or finished for the duration
+func (n *networkTracker) idle(duration time.Duration) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.inFlight) == 0 && time.Since(n.lastActivity) >= duration
+}
+
+// wait waits for the network to be idle for the duration
+//
+// Returns:
+//   - error: If the context is canceled or times out first.
+func (n *networkTracker) wait(ctx context.Context, duration time.Duration) error {
+	ticker := time.NewTicker(networkIdlePoll)
+	defer ticker.Stop()
+	for !n.idle(duration) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// waitForContent waits for the wait-selector and wait-idle flags before the DOM is searched
+//
+// Only HTML pages are waited for, since scripts and other files found on the pages won't have the selector in them. The
+// waits only get half of the timeout between them, so if the selector never shows up or the network is never idle, the
+// DOM is still searched with a warning rather than the whole search timing out.
+//
+// Parameters:
+//   - tracker: The networkTracker listening to the tab.
+//   - url: The URL of the page, for the warnings.
+//
+// Returns:
+//   - chromedp.Action: The action to run after navigating to the page.
+func (s *scanner) waitForContent(tracker *networkTracker, url string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if s.domWaitSelector == "" && s.domWaitIdle == 0 {
+			return nil
+		}
+		var contentType string
+		err := chromedp.Evaluate(`document.contentType`, &contentType).Do(ctx)
+		if err != nil || contentType != "text/html" {
+			return err
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, s.requestTimeout/2)
+		defer cancel()
+		if s.domWaitSelector != "" {
+			err = waitOrWarn(ctx, waitCtx, func(ctx context.Context) error {
+				return chromedp.WaitVisible(s.domWaitSelector, chromedp.ByQuery).Do(ctx)
+			}, fmt.Sprintf("%s never matched the wait-selector %q, searching the DOM anyway", url, s.domWaitSelector))
+			if err != nil {
+				return err
+			}
+		}
+		if s.domWaitIdle > 0 {
+			return waitOrWarn(ctx, waitCtx, func(ctx context.Context) error {
+				return tracker.wait(ctx, s.domWaitIdle)
+			}, fmt.Sprintf("The network for %s was never idle for %s, searching the DOM anyway", url, s.domWaitIdle))
+		}
+		return nil
+	})
+}
+
+// waitOrWarn runs one of the waits for waitForContent, and logs a warning instead of returning an error if it runs out
+// of time
+//
+// Parameters:
+//   - ctx: The context of the DOM search, if it's done the error is returned since there's no time left to search.
+//   - waitCtx: The context with the time the waits get.
+//   - wait: The wait to run with the waitCtx.
+//   - warning: The warning to log if the waitCtx runs out.
+//
+// Returns:
+//   - error: The error from the wait, unless the waitCtx ran out first.
+func waitOrWarn(ctx context.Context, waitCtx context.Context, wait func(ctx context.Context) error, warning string) error {
+	err := wait(waitCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+		//Non-breaking, whatever has rendered so far is still worth searching
+		fmt.Fprintf(os.Stderr, "Warning - %s\n", warning)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkTracker(t *testing.T) {
+	tracker := newNetworkTracker()
+
+	//Test case: Not idle while a request is in flight, or until the duration has passed since the last one
+	tracker.handle(&network.EventRequestWillBeSent{RequestID: "1"})
+	tracker.handle(&network.EventRequestWillBeSent{RequestID: "2"})
+	assert.False(t, tracker.idle(0), "Expected requests in flight")
+	tracker.handle(&network.EventLoadingFinished{RequestID: "1"})
+	tracker.handle(&network.EventLoadingFailed{RequestID: "2"})
+	assert.True(t, tracker.idle(0), "Expected no requests in flight")
+	assert.False(t, tracker.idle(time.Hour), "Expected the network to have been busy recently")
+
+	//Test case: Other events don't count as activity
+	tracker.lastActivity = time.Now().Add(-time.Second)
+	tracker.handle(&network.EventResponseReceived{RequestID: "3"})
+	assert.True(t, tracker.idle(500*time.Millisecond), "Expected other events to be ignored")
+
+	//Test case: Waiting returns once the network is idle, or when the context is done
+	tracker.handle(&network.EventRequestWillBeSent{RequestID: "4"})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		tracker.handle(&network.EventLoadingFinished{RequestID: "4"})
+	}()
+	err := tracker.wait(context.TODO(), 10*time.Millisecond)
+	assert.Nil(t, err, "Unexpected error")
+	tracker.handle(&network.EventRequestWillBeSent{RequestID: "5"})
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	err = tracker.wait(ctx, 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected the wait to time out")
+
+	//Test case: The headers of the page's response are kept, and not the ones of iframes or other requests
+	tracker = newNetworkTracker()
+	tracker.handle(&network.EventRequestWillBeSent{RequestID: "1", Type: network.ResourceTypeDocument})
+	tracker.handle(&network.EventRequestWillBeSent{RequestID: "2", Type: network.ResourceTypeDocument})
+	tracker.handle(&network.EventResponseReceived{RequestID: "2", Response: &network.Response{Headers: network.Headers{"X-Frame-Options": "SAMEORIGIN"}}})
+	assert.Nil(t, tracker.pageHeader(), "Expected no headers before the page's response")
+	tracker.handle(&network.EventResponseReceived{RequestID: "1", Response: &network.Response{Headers: network.Headers{"content-security-policy": "default-src 'self'\nscript-src 'self'"}}})
+	assert.Equal(t, http.Header{"Content-Security-Policy": {"default-src 'self'", "script-src 'self'"}}, tracker.pageHeader(), "Expected each value of the page's headers")
+}
+
+func TestWaitOrWarn(t *testing.T) {
+	waitForever := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	//Test case: A wait that runs out of its time is a warning, so the DOM is still searched
+	waitCtx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	err := waitOrWarn(context.TODO(), waitCtx, waitForever, "never matched")
+	assert.Nil(t, err, "Expected the wait running out not to be an error")
+
+	//Test case: Errors from the wait are returned
+	err = waitOrWarn(context.TODO(), context.TODO(), func(ctx context.Context) error { return errors.New("tab closed") }, "never matched")
+	assert.EqualError(t, err, "tab closed", "Expected the error from the wait")
+
+	//Test case: The search being stopped or timing out is returned, since there's no time left to search the DOM
+	ctx, cancelSearch := context.WithCancel(context.TODO())
+	cancelSearch()
+	err = waitOrWarn(ctx, ctx, waitForever, "never matched")
+	assert.ErrorIs(t, err, context.Canceled, "Expected the search's error")
+}
@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The standard introspection query that GraphQL clients like GraphiQL send to get the whole schema
+const graphqlIntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) { name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// The parts of an introspection response with strings worth reporting in them
+type graphqlResponse struct {
+	Data *struct {
+		Schema *graphqlSchema `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type graphqlSchema struct {
+	Types      []graphqlType  `json:"types"`
+	Directives []graphqlField `json:"directives"`
+}
+
+type graphqlType struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Fields      []graphqlField      `json:"fields"`
+	InputFields []graphqlInputValue `json:"inputFields"`
+	EnumValues  []graphqlField      `json:"enumValues"`
+}
+
+// A graphqlField is a field, enum value or directive, which all have a name, description and deprecation reason
+type graphqlField struct {
+	Name              string              `json:"name"`
+	Description       string              `json:"description"`
+	DeprecationReason string              `json:"deprecationReason"`
+	Args              []graphqlInputValue `json:"args"`
+}
+
+type graphqlInputValue struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	DefaultValue string `json:"defaultValue"`
+}
+
+// Types and directives that are built in to every GraphQL server, which only have the standard descriptions from the spec
+var graphqlBuiltins = map[string]struct{}{
+	"String": {}, "Int": {}, "Float": {}, "Boolean": {}, "ID": {},
+	"include": {}, "skip": {}, "deprecated": {}, "specifiedBy": {}, "oneOf": {},
+}
+
+// graphqlRequestBody builds the JSON body to POST the introspection query to a GraphQL endpoint
+func graphqlRequestBody() string {
+	body, _ := json.Marshal(map[string]string{"query": graphqlIntrospectionQuery, "operationName": "IntrospectionQuery"})
+	return string(body)
+}
+
+// getGraphQLStrings gets the names, descriptions, default values and deprecation reasons from an introspection response
+//
+// These are where schemas leak internal field names and sample tokens, so they're reported instead of every value in
+// the response, which would include things like the kind of every type. The built in types and directives, like String and
+// the ones starting with __, are skipped.
+//
+// Parameters:
+//   - text: The JSON response to the introspection query.
+//
+// Returns:
+//   - []string: A slice of strings containing the findings.
+//   - error: If the response isn't JSON or doesn't have a schema, like when introspection is turned off.
+func (s *scanner) getGraphQLStrings(text string) ([]string, error) {
+	var response graphqlResponse
+	err := json.Unmarshal([]byte(text), &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Data == nil || response.Data.Schema == nil {
+		if len(response.Errors) > 0 {
+			return nil, fmt.Errorf("no schema returned: %s", response.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("no schema returned")
+	}
+
+	var result []string
+	add := func(strs ...string) {
+		for _, str := range strs {
+			if str != "" {
+				result = append(result, str)
+			}
+		}
+	}
+	addInputValues := func(values []graphqlInputValue) {
+		for _, value := range values {
+			add(value.Name, value.Description, value.DefaultValue)
+		}
+	}
+	addFields := func(fields []graphqlField) {
+		for _, field := range fields {
+			add(field.Name, field.Description, field.DeprecationReason)
+			addInputValues(field.Args)
+		}
+	}
+
+	for _, schemaType := range response.Data.Schema.Types {
+		if _, builtin := graphqlBuiltins[schemaType.Name]; builtin || strings.HasPrefix(schemaType.Name, "__") {
+			continue
+		}
+		add(schemaType.Name, schemaType.Description)
+		addFields(schemaType.Fields)
+		addInputValues(schemaType.InputFields)
+		addFields(schemaType.EnumValues)
+	}
+	for _, directive := range response.Data.Schema.Directives {
+		if _, builtin := graphqlBuiltins[directive.Name]; !builtin {
+			addFields([]graphqlField{directive})
+		}
+	}
+	return s.filterLength(result), nil
+}
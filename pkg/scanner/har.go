@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Captured headers that aren't replayed, since they're about the connection the request was captured on or are set
+// by webstrings itself. HTTP/2 pseudo-headers like :authority are skipped as well.
+var skippedReplayHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"Te":                true,
+	"Accept-Encoding":   true,
+	"User-Agent":        true,
+}
+
+// A HARRequest is a request captured in a HAR file, like a Burp Suite or OWASP ZAP proxy history export
+type HARRequest struct {
+	URL string
+	//The headers sent with the request, including a Cookie header with its cookies
+	Headers http.Header
+}
+
+// The parts of a HAR file that are needed, see http://www.softwareishard.com/blog/har-12-spec/
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				URL     string      `json:"url"`
+				Headers []harRecord `json:"headers"`
+				Cookies []harRecord `json:"cookies"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// A harRecord is a name and value pair, used for both the headers and cookies of a request
+type harRecord struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// LoadHAR reads the requests from a HAR file, for searching the URLs from a proxy history
+//
+// Only http and https requests are kept, so data: URLs and WebSocket requests are left out.
+//
+// Parameters:
+//   - path: The path to the HAR file.
+//
+// Returns:
+//   - []HARRequest: The requests in the file, in the order they were captured.
+//   - error: If the file can't be read or isn't valid JSON.
+func LoadHAR(path string) ([]HARRequest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseHAR(file)
+}
+
+// parseHAR parses the requests from a HAR file, see LoadHAR
+func parseHAR(r io.Reader) ([]HARRequest, error) {
+	var har harFile
+	err := json.NewDecoder(r).Decode(&har)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []HARRequest
+	for _, entry := range har.Log.Entries {
+		url := entry.Request.URL
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			continue
+		}
+		headers := http.Header{}
+		for _, header := range entry.Request.Headers {
+			name := http.CanonicalHeaderKey(header.Name)
+			if strings.HasPrefix(name, ":") || skippedReplayHeaders[name] {
+				continue
+			}
+			headers.Add(name, header.Value)
+		}
+		//Some exports only have the cookies in their own list, so the Cookie header is rebuilt from it
+		if headers.Get("Cookie") == "" && len(entry.Request.Cookies) > 0 {
+			cookies := make([]string, 0, len(entry.Request.Cookies))
+			for _, cookie := range entry.Request.Cookies {
+				cookies = append(cookies, cookie.Name+"="+cookie.Value)
+			}
+			headers.Set("Cookie", strings.Join(cookies, "; "))
+		}
+		requests = append(requests, HARRequest{URL: url, Headers: headers})
+	}
+	return requests, nil
+}
+
+// addReplayHeaders adds the headers captured for a URL to a request, see ReplayHeaders in the Options
+//
+// Headers that are already set, like from the header flag, are left alone, so they can be used to replace an expired
+// token from the capture.
+//
+// Parameters:
+//   - header: The headers of the request.
+//   - url: The URL of the request.
+func (s *scanner) addReplayHeaders(header http.Header, url string) {
+	for name, values := range s.replayHeaders[normalizeURL(url)] {
+		if header.Get(name) != "" {
+			continue
+		}
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+}
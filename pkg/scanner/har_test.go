@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHAR(t *testing.T) {
+	har := `{"log": {"version": "1.2", "entries": [
+		{"request": {"method": "GET", "url": "https://example.com/app.js", "headers": [
+			{"name": ":authority", "value": "example.com"},
+			{"name": "host", "value": "example.com"},
+			{"name": "authorization", "value": "Bearer abc123"},
+			{"name": "Cookie", "value": "session=1"},
+			{"name": "Accept-Encoding", "value": "gzip, zstd"}
+		], "cookies": [{"name": "session", "value": "1"}]}},
+		{"request": {"method": "GET", "url": "data:image/png;base64,iVBORw0KGgo=", "headers": []}},
+		{"request": {"method": "GET", "url": "wss://example.com/socket", "headers": []}},
+		{"request": {"method": "POST", "url": "http://example.com/api", "headers": [], "cookies": [
+			{"name": "a", "value": "1"}, {"name": "b", "value": "2"}
+		]}}
+	]}}`
+
+	//Test case: Only http and https requests are kept, without the headers about the connection
+	requests, err := parseHAR(strings.NewReader(har))
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []HARRequest{
+		{URL: "https://example.com/app.js", Headers: http.Header{"Authorization": {"Bearer abc123"}, "Cookie": {"session=1"}}},
+		{URL: "http://example.com/api", Headers: http.Header{"Cookie": {"a=1; b=2"}}},
+	}, requests, "Unexpected requests")
+
+	//Test case: Invalid JSON
+	_, err = parseHAR(strings.NewReader(`{"log": `))
+	assert.NotNil(t, err, "Expected an error for invalid JSON")
+}
+
+func TestReplayHeaders(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s", r.Header.Get("Authorization"), r.Header.Get("Cookie"))
+	}))
+	defer mockServer.Close()
+	opts := DefaultOptions()
+	opts.ReplayHeaders = map[string]http.Header{
+		mockServer.URL + "/app.js#main": {"Authorization": {"Bearer captured"}, "Cookie": {"session=1"}},
+	}
+	s, _, err := configure(&opts)
+	assert.Nil(t, err, "Unexpected error")
+
+	//Test case: The captured headers are sent with their URL, matched after normalizing it
+	result, _, err := s.getContents(context.TODO(), mockServer.URL+"/app.js", mockServer.URL)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, "Bearer captured|session=1", *result, "Expected the captured headers")
+
+	//Test case: Other URLs don't get them
+	result, _, err = s.getContents(context.TODO(), mockServer.URL+"/other.js", mockServer.URL)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, "|", *result, "Expected no captured headers")
+
+	//Test case: Headers from the header flags are used over the captured ones
+	opts.Bearer = "fresh"
+	s, _, err = configure(&opts)
+	assert.Nil(t, err, "Unexpected error")
+	result, _, err = s.getContents(context.TODO(), mockServer.URL+"/app.js", mockServer.URL)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, "Bearer fresh|session=1", *result, "Expected the header flag to be used")
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"net"
+	"strings"
+)
+
+// Finding type for private IP addresses and internal hostnames, which point to infrastructure that shouldn't be
+// referenced from the frontend
+const internalAddressFinding = "Private/Internal Address"
+
+// Candidates for internalAddressFinding, used when the urls or internal flag is enabled
+//
+// This matches any IPv4 or IPv6 literal and any hostname with an internal suffix, and internalAddress checks each
+// match, since the private ranges are much easier to check after parsing the address than in a regex.
+const internalAddressRegex = `\b(?:\d{1,3}\.){3}\d{1,3}\b` +
+	`|[0-9a-fA-F]{0,4}(?::[0-9a-fA-F]{0,4}){2,7}` +
+	`|\b[a-zA-Z0-9][a-zA-Z0-9.-]*\.(?:local|internal|intranet|corp|lan)\b`
+
+// Suffixes of hostnames that are only resolvable inside a network, like mDNS names and internal DNS zones
+var internalHostSuffixes = []string{".local", ".internal", ".intranet", ".corp", ".lan"}
+
+// internalAddress checks if a match for internalAddressRegex is a private IP address or an internal hostname
+//
+// IP addresses have to be in a private range, like 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16 or fc00::/7, so public
+// addresses, loopback addresses and version numbers that look like addresses are left out. Hostnames have to be at the
+// start of a string, URL or email address, so property accesses in scripts like chrome.storage.local are left out.
+//
+// Parameters:
+//   - text: The text the match was found in.
+//   - start: The byte index of the start of the match.
+//   - end: The byte index of the end of the match.
+//
+// Returns:
+//   - bool: True if the match should be reported.
+func internalAddress(text string, start int, end int) bool {
+	value := text[start:end]
+	var before, after byte
+	if start > 0 {
+		before = text[start-1]
+	}
+	if end < len(text) {
+		after = text[end]
+	}
+	//Part of something longer, like 1.10.0.0.1 or a hostname that goes on to more labels
+	if after == '.' || after == '-' || isWordByte(after) || before == '.' || before == '-' || isWordByte(before) {
+		return false
+	}
+
+	if ip := net.ParseIP(value); ip != nil {
+		return ip.IsPrivate()
+	}
+	if !strings.ContainsRune(`'"`+"`"+`/@`, rune(before)) {
+		return false
+	}
+	host := strings.ToLower(value)
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordByte checks if a byte is a letter, number or underscore, like \w in a regex
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
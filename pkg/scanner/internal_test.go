@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternalAddress(t *testing.T) {
+	//Test case: Private IPv4 ranges
+	for _, address := range []string{"10.0.0.1", "10.255.255.255", "172.16.0.1", "172.31.255.254", "192.168.1.20"} {
+		text := `"` + address + `"`
+		assert.True(t, internalAddress(text, 1, len(text)-1), "Expected %s to be private", address)
+	}
+
+	//Test case: Public IPv4 addresses, including the ones just outside the private ranges
+	for _, address := range []string{"8.8.8.8", "172.15.255.255", "172.32.0.1", "192.169.0.1", "11.0.0.1", "127.0.0.1"} {
+		text := `"` + address + `"`
+		assert.False(t, internalAddress(text, 1, len(text)-1), "Expected %s to be public", address)
+	}
+
+	//Test case: IPv6 unique local addresses are private, global ones are not
+	assert.True(t, internalAddress("[fd12:3456:789a::1]", 1, 18), "Expected a unique local address to be private")
+	assert.False(t, internalAddress("[2001:db8::1]", 1, 12), "Expected a global address to be public")
+
+	//Test case: Internal hostnames in strings and URLs, but not property accesses
+	assert.True(t, internalAddress(`"db.internal"`, 1, 12), "Expected a hostname in a string")
+	assert.True(t, internalAddress("http://printer.local/", 7, 20), "Expected a hostname in a URL")
+	assert.False(t, internalAddress("chrome.storage.local.get()", 7, 20), "Expected a property access to be left out")
+
+	//Test case: Addresses that are part of something longer
+	assert.False(t, internalAddress("v10.0.0.1", 1, 9), "Expected a version number to be left out")
+	assert.False(t, internalAddress("1.10.0.0.1", 2, 10), "Expected part of a longer number to be left out")
+}
+
+func TestGetSecretsInternal(t *testing.T) {
+	text := `const api = "http://10.0.12.5:8080/v1"; const dns = "8.8.8.8"; const db = "postgres@db.corp:5432";` +
+		` const mirror = "https://[fd00::2]/"; const v = "1.10.0.0.1"; chrome.storage.local.get(); const home = "192.168.0.1"`
+
+	opts := DefaultOptions()
+	opts.Secrets = true
+
+	//Test case: Only private addresses and internal hostnames are found
+	opts.Internal = true
+	findings, err := GetSecrets(text, opts)
+	assert.Nil(t, err, "Unexpected error")
+	var values []string
+	for _, finding := range findings {
+		assert.Equal(t, internalAddressFinding, finding.Type, "Unexpected type")
+		values = append(values, finding.Value)
+	}
+	assert.Equal(t, []string{"10.0.12.5", "db.corp", "fd00::2", "192.168.0.1"}, values, "Unexpected addresses")
+
+	//Test case: The urls flag searches for them as well
+	opts.Internal, opts.URLs = false, true
+	findings, err = GetSecrets(`"http://10.0.12.5/"`, opts)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Contains(t, findings, Finding{Type: internalAddressFinding, Value: "10.0.12.5", Line: 1, Column: 9}, "Expected the address with the urls flag")
+
+	//Test case: Not searched for without either flag
+	opts.URLs = false
+	findings, err = GetSecrets(`"http://10.0.12.5/"`, opts)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Empty(t, findings, "Expected no addresses without the internal or urls flag")
+}
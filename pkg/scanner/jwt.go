@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The secretRegex description for JWTs, which getSecrets adds the decoded claims to
+const jwtFinding = "JSON Web Token"
+
+// jwtDetails decodes the header and payload of a JWT to describe it
+//
+// The alg from the header and the iss and exp claims from the payload are included if they are there. Tokens that
+// aren't signed (alg: none) are marked as unsigned and tokens past their exp are marked as expired, since an unsigned
+// token means the server might accept forged ones and an expired one is less likely to be useful.
+//
+// Parameters:
+//   - token: The JWT, in the header.payload.signature format.
+//
+// Returns:
+//   - string: The details, like "alg: HS256, iss: https://auth.example.com, exp: 2030-01-01T00:00:00Z", or an empty
+//     string if the token can't be decoded.
+func jwtDetails(token string) string {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return ""
+	}
+	var header map[string]interface{}
+	var payload map[string]interface{}
+	if decodeJWTSegment(segments[0], &header) != nil || decodeJWTSegment(segments[1], &payload) != nil {
+		return ""
+	}
+
+	var details []string
+	if alg, ok := header["alg"].(string); ok {
+		if strings.EqualFold(alg, "none") {
+			details = append(details, "alg: "+alg+" (unsigned)")
+		} else {
+			details = append(details, "alg: "+alg)
+		}
+	}
+	if iss, ok := payload["iss"].(string); ok {
+		details = append(details, "iss: "+iss)
+	}
+	//exp is a unix timestamp in seconds
+	if exp, ok := payload["exp"].(float64); ok {
+		expires := time.Unix(int64(exp), 0).UTC()
+		if expires.Before(time.Now()) {
+			details = append(details, "exp: "+expires.Format(time.RFC3339)+" (expired)")
+		} else {
+			details = append(details, "exp: "+expires.Format(time.RFC3339))
+		}
+	}
+	return strings.Join(details, ", ")
+}
+
+// decodeJWTSegment decodes the base64url JSON in the header or payload of a JWT into v
+func decodeJWTSegment(segment string, v interface{}) error {
+	//JWTs shouldn't be padded, but some libraries do it anyway
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return fmt.Errorf("invalid JWT segment: %w", err)
+	}
+	return json.Unmarshal(decoded, v)
+}
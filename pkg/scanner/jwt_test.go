@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testJWT builds a JWT from the JSON of its header and payload, the signature isn't checked so it can be anything
+func testJWT(header string, payload string, signature string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signature
+}
+
+func TestJWTDetails(t *testing.T) {
+	//Test case: Signed token that hasn't expired
+	token := testJWT(`{"alg":"HS256","typ":"JWT"}`, `{"iss":"https://auth.example.com","exp":4102444800}`, "c2lnbmF0dXJl")
+	assert.Equal(t, "alg: HS256, iss: https://auth.example.com, exp: 2100-01-01T00:00:00Z", jwtDetails(token), "Unexpected details")
+
+	//Test case: Unsigned and expired tokens are marked
+	token = testJWT(`{"alg":"none"}`, `{"sub":"admin","exp":946684800}`, "")
+	assert.Equal(t, "alg: none (unsigned), exp: 2000-01-01T00:00:00Z (expired)", jwtDetails(token), "Unexpected details")
+
+	//Test case: Missing claims are left out
+	token = testJWT(`{"typ":"JWT"}`, `{"sub":"admin"}`, "c2lnbmF0dXJl")
+	assert.Empty(t, jwtDetails(token), "Expected no details")
+
+	//Test case: Tokens that can't be decoded
+	assert.Empty(t, jwtDetails("eyJhbGciOi.eyJzdWIiOi"), "Expected no details for a token without a signature segment")
+	assert.Empty(t, jwtDetails("eyJ!!!!!!.eyJzdWIiOi.c2ln"), "Expected no details for invalid base64")
+	assert.Empty(t, jwtDetails(testJWT(`{"alg":`, `{}`, "")), "Expected no details for invalid JSON")
+}
+
+func TestGetSecretsJWT(t *testing.T) {
+	s := newScanner()
+	opts := Options{Secrets: true}
+	s.compiledSecretRegex = s.compileSecretRegex(opts)
+
+	//Test case: JWTs are found and decoded, including unsigned ones without a signature
+	signed := testJWT(`{"alg":"RS256"}`, `{"iss":"example"}`, "c2lnbmF0dXJl")
+	unsigned := testJWT(`{"alg":"none"}`, `{"iss":"example"}`, "")
+	results := s.getSecrets(`headers: {Authorization: "Bearer `+signed+`"}, fallback: "`+unsigned+`"`, opts)
+	expected := []secretMatch{
+		{Value: signed, Details: "alg: RS256, iss: example", Line: 1, Column: 34, Start: 33},
+		{Value: unsigned, Details: "alg: none (unsigned), iss: example", Line: 1, Column: 106, Start: 105},
+	}
+	assert.Equal(t, expected, results[jwtFinding], "Unexpected JWT findings")
+}
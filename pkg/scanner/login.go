@@ -0,0 +1,258 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+	"gopkg.in/yaml.v3"
+)
+
+// The password field that is filled in by the login-url flag, the first match on the login page is used
+const loginPasswordSelector = `input[type="password"]`
+
+// The selectors for the username field that is filled in by the login-url flag, tried one at a time in this order in the
+// form with the password field, so a search box earlier on the page isn't picked over the actual username field
+var loginUserSelectors = []string{
+	`input[autocomplete="username"]`,
+	`input[type="email"]`,
+	`input[name*="user" i]`,
+	`input[name*="login" i]`,
+	`input[name*="email" i]`,
+	`input[type="text"]`,
+}
+
+// The attribute the find-user step marks the username field with, so the type step after it can find it
+const (
+	loginUserAttribute = "data-webstrings-login-user"
+	loginUserSelector  = "[" + loginUserAttribute + "]"
+)
+
+// A loginStep is one action in a login script
+//
+// The action is one of:
+//   - navigate: Go to the URL in Value.
+//   - click: Click the element matching Selector.
+//   - type: Type Value into the element matching Selector.
+//   - submit: Submit the form that the element matching Selector is in.
+//   - wait: Wait for the element matching Selector to be visible.
+//   - wait-gone: Wait for the element matching Selector to be removed, like the login form after logging in.
+//   - sleep: Wait for the duration in Value, like 2s.
+//
+// Environment variables in the Value, like $PASSWORD or ${PASSWORD}, are expanded so the script doesn't need to have
+// the credentials in it.
+//
+// The steps from formLoginSteps also use find-user, which marks the username field with loginUserAttribute. It isn't
+// allowed in login scripts.
+type loginStep struct {
+	Action   string `json:"action" yaml:"action"`
+	Selector string `json:"selector" yaml:"selector"`
+	Value    string `json:"value" yaml:"value"`
+}
+
+// loadLoginScript reads a login script, which is a list of loginSteps
+//
+// Files ending in .json are parsed as JSON and anything else is parsed as YAML, like the patterns file. See the README
+// for an example.
+//
+// Parameters:
+//   - path: The path to the login script.
+//
+// Returns:
+//   - []loginStep: The steps in the script.
+//   - error: If the file can't be read or parsed, or a step is invalid.
+func loadLoginScript(path string) ([]loginStep, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []loginStep
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		err = json.Unmarshal(file, &steps)
+	} else {
+		err = yaml.Unmarshal(file, &steps)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse login script %s: %w", path, err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("login script %s has no steps", path)
+	}
+
+	for i, step := range steps {
+		step.Action = strings.ToLower(strings.TrimSpace(step.Action))
+		step.Value = os.ExpandEnv(step.Value)
+		switch step.Action {
+		case "navigate":
+			if step.Value == "" {
+				return nil, fmt.Errorf("invalid login script %s: step %d needs a value with the URL to navigate to", path, i+1)
+			}
+		case "click", "type", "submit", "wait", "wait-gone":
+			if step.Selector == "" {
+				return nil, fmt.Errorf("invalid login script %s: step %d needs a selector", path, i+1)
+			}
+		case "sleep":
+			if _, err := time.ParseDuration(step.Value); err != nil {
+				return nil, fmt.Errorf("invalid login script %s: step %d needs a duration like 2s: %w", path, i+1, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid login script %s: step %d has an unknown action %q", path, i+1, step.Action)
+		}
+		steps[i] = step
+	}
+	return steps, nil
+}
+
+// formLoginSteps builds the steps for the login-url flag, which fills in the username and password fields on a login
+// page and submits the form
+//
+// The login is done once the password field is gone, which works for pages that redirect after logging in and for
+// single page apps that only swap out the form.
+func formLoginSteps(url string, user string, password string) []loginStep {
+	return []loginStep{
+		{Action: "navigate", Value: addScheme(url)},
+		{Action: "wait", Selector: loginPasswordSelector},
+		{Action: "find-user"},
+		{Action: "type", Selector: loginUserSelector, Value: user},
+		{Action: "type", Selector: loginPasswordSelector, Value: password},
+		{Action: "submit", Selector: loginPasswordSelector},
+		{Action: "wait-gone", Selector: loginPasswordSelector},
+	}
+}
+
+// chromedpAction converts a loginStep to the chromedp action that runs it
+func (s loginStep) chromedpAction() chromedp.Action {
+	switch s.Action {
+	case "navigate":
+		return chromedp.Navigate(s.Value)
+	case "click":
+		return chromedp.Click(s.Selector, chromedp.ByQuery)
+	case "type":
+		return chromedp.SendKeys(s.Selector, s.Value, chromedp.ByQuery)
+	case "submit":
+		return chromedp.Submit(s.Selector, chromedp.ByQuery)
+	case "wait":
+		return chromedp.WaitVisible(s.Selector, chromedp.ByQuery)
+	case "wait-gone":
+		return chromedp.WaitNotPresent(s.Selector, chromedp.ByQuery)
+	case "find-user":
+		return findLoginUser()
+	default:
+		//loadLoginScript has already checked the durations
+		duration, _ := time.ParseDuration(s.Value)
+		return chromedp.Sleep(duration)
+	}
+}
+
+// findLoginUser marks the username field for the login-url flag with loginUserAttribute
+//
+// Each of the loginUserSelectors is tried in order, in the form that has the password field or the whole page if it
+// isn't in a form, and the first field found is used.
+//
+// Returns:
+//   - chromedp.Action: The action to run once the password field is visible, which errors if there's no username field.
+func findLoginUser() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		//Marshalled so the quotes in the selectors are escaped for the script
+		selectors, err := json.Marshal(loginUserSelectors)
+		if err != nil {
+			return err
+		}
+		script := fmt.Sprintf(`(() => {
+			const password = document.querySelector(%q);
+			const scope = (password && password.form) || document;
+			for (const selector of %s) {
+				const field = scope.querySelector(selector);
+				if (field) {
+					field.setAttribute(%q, "");
+					return true;
+				}
+			}
+			return false;
+		})()`, loginPasswordSelector, selectors, loginUserAttribute)
+		var found bool
+		err = chromedp.Evaluate(script, &found).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.New("couldn't find the username field on the login page")
+		}
+		return nil
+	})
+}
+
+// browserLogin runs the login steps in the headless browser and gets the cookies it ends up with
+//
+// The whole login has to finish within the timeout, the same as a single DOM search.
+//
+// Parameters:
+//   - parentCtx: The context for the run, used to cancel the login if needed.
+//   - steps: The login steps, see loadLoginScript and formLoginSteps.
+//
+// Returns:
+//   - []*http.Cookie: Every cookie in the browser after logging in, with their Domain set.
+//   - error: If a step fails or the login times out.
+func (s *scanner) browserLogin(parentCtx context.Context, steps []loginStep) ([]*http.Cookie, error) {
+	allocatorCtx, cancelAllocator := chromedp.NewExecAllocator(parentCtx, s.newAllocatorOptions()...)
+	defer cancelAllocator()
+	ctx, cancel := chromedp.NewContext(allocatorCtx)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, s.requestTimeout)
+	defer cancelTimeout()
+
+	headers := network.Headers{}
+	for name, values := range s.requestHeaders {
+		headers[name] = strings.Join(values, ", ")
+	}
+	actions := []chromedp.Action{network.Enable(), network.SetExtraHTTPHeaders(headers)}
+	for _, step := range steps {
+		actions = append(actions, step.chromedpAction())
+	}
+
+	var browserCookies []*network.Cookie
+	actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		browserCookies, err = storage.GetCookies().Do(ctx)
+		return err
+	}))
+	err := chromedp.Run(ctx, actions...)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("login timed out after %s", s.requestTimeout)
+	} else if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+	return httpCookies(browserCookies), nil
+}
+
+// httpCookies converts the cookies from the headless browser so they can be added to requestCookies
+//
+// The browser gives domain cookies a leading dot and host-only cookies none, the same as the cookie file, so
+// newCookieJar and cookieParams treat them the same way the browser did.
+func httpCookies(browserCookies []*network.Cookie) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, browserCookie := range browserCookies {
+		cookie := &http.Cookie{
+			Name:     browserCookie.Name,
+			Value:    browserCookie.Value,
+			Domain:   browserCookie.Domain,
+			Path:     browserCookie.Path,
+			Secure:   browserCookie.Secure,
+			HttpOnly: browserCookie.HTTPOnly,
+		}
+		if !browserCookie.Session && browserCookie.Expires > 0 {
+			cookie.Expires = time.Unix(int64(browserCookie.Expires), 0)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLoginScript(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("WEBSTRINGS_TEST_PASSWORD", "hunter2")
+	write := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte(contents), 0644)
+		assert.Nil(t, err, "Unexpected error writing login script")
+		return path
+	}
+
+	//Test case: YAML script, with the action normalized and environment variables expanded in values
+	path := write("login.yaml", `
+- action: navigate
+  value: https://example.com/login
+- action: " Type "
+  selector: "#password"
+  value: ${WEBSTRINGS_TEST_PASSWORD}
+- action: sleep
+  value: 1s
+`)
+	steps, err := loadLoginScript(path)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []loginStep{
+		{Action: "navigate", Value: "https://example.com/login"},
+		{Action: "type", Selector: "#password", Value: "hunter2"},
+		{Action: "sleep", Value: "1s"},
+	}, steps, "Unexpected steps")
+
+	//Test case: JSON script
+	path = write("login.json", `[{"action": "click", "selector": "#sign-in"}, {"action": "wait-gone", "selector": "form"}]`)
+	steps, err = loadLoginScript(path)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []loginStep{{Action: "click", Selector: "#sign-in"}, {Action: "wait-gone", Selector: "form"}}, steps, "Unexpected steps")
+
+	//Test case: Invalid scripts
+	_, err = loadLoginScript(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(t, err, "Expected error for missing login script")
+	_, err = loadLoginScript(write("empty.yaml", ""))
+	assert.NotNil(t, err, "Expected error for login script without steps")
+	_, err = loadLoginScript(write("unknown.yaml", "- action: hover\n  selector: a\n"))
+	assert.NotNil(t, err, "Expected error for unknown action")
+	_, err = loadLoginScript(write("selector.yaml", "- action: click\n"))
+	assert.NotNil(t, err, "Expected error for click without a selector")
+	_, err = loadLoginScript(write("sleep.yaml", "- action: sleep\n  value: soon\n"))
+	assert.NotNil(t, err, "Expected error for sleep without a duration")
+}
+
+func TestLoginOptions(t *testing.T) {
+	//Test case: The login-url flag fills in the form on the login page and waits for it to go away
+	opts := DefaultOptions()
+	opts.DOM = true
+	opts.LoginURL = "example.com/login"
+	opts.LoginUser = "me@example.com"
+	opts.LoginPassword = "hunter2"
+	s, _, err := configure(&opts)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, formLoginSteps("https://example.com/login", "me@example.com", "hunter2"), s.loginSteps, "Unexpected login steps")
+	assert.Equal(t, "https://example.com/login", s.loginSteps[0].Value, "Expected the scheme to be added to the login URL")
+	assert.Equal(t, "wait-gone", s.loginSteps[len(s.loginSteps)-1].Action, "Expected the login to wait for the form to go away")
+	assert.Equal(t, []loginStep{{Action: "find-user"}, {Action: "type", Selector: "[data-webstrings-login-user]", Value: "me@example.com"}}, s.loginSteps[2:4], "Expected the username to be typed into the field that was found")
+
+	//Test case: The username field that was found can't be used in a login script
+	path := filepath.Join(t.TempDir(), "login.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte("- action: find-user\n"), 0644), "Unexpected error writing login script")
+	_, err = loadLoginScript(path)
+	assert.NotNil(t, err, "Expected error for the find-user action in a login script")
+
+	//Test case: Invalid combinations
+	opts.DOM = false
+	_, _, err = configure(&opts)
+	assert.EqualError(t, err, "the login-script and login-url flags need the dom flag", "Expected an error without the dom flag")
+	opts.DOM = true
+	opts.LoginPassword = ""
+	_, _, err = configure(&opts)
+	assert.EqualError(t, err, "login-url needs login-user and login-pass", "Expected an error without a password")
+	opts.LoginScript = "login.yaml"
+	_, _, err = configure(&opts)
+	assert.EqualError(t, err, "the login-script and login-url flags can't be used together", "Expected an error with both login flags")
+}
+
+func TestHTTPCookies(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	//Test case: Domain and host-only cookies keep their Domain, and session cookies don't get an expiry
+	cookies := httpCookies([]*network.Cookie{
+		{Name: "session", Value: "abc123", Domain: "example.com", Path: "/", Secure: true, HTTPOnly: true, Session: true, Expires: -1},
+		{Name: "prefs", Value: "dark", Domain: ".example.com", Path: "/", Expires: float64(expires.Unix())},
+	})
+	assert.Equal(t, []*http.Cookie{
+		{Name: "session", Value: "abc123", Domain: "example.com", Path: "/", Secure: true, HttpOnly: true},
+		{Name: "prefs", Value: "dark", Domain: ".example.com", Path: "/", Expires: expires},
+	}, cookies, "Unexpected cookies")
+}
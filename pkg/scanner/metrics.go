@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics are the counts from a run written with the metrics flag, for keeping track of scheduled scans
+type Metrics struct {
+	//Every HTTP request sent for the pages and scripts searched, including retries
+	Requests     int64 `json:"requests"`
+	URLsSearched int64 `json:"urls_searched"`
+	URLsFailed   int64 `json:"urls_failed"`
+	//The URLs that couldn't be fetched by the status code they returned, or "error" if there wasn't a response
+	FailuresByStatus map[string]int `json:"failures_by_status"`
+	FindingsByType   map[string]int `json:"findings_by_type"`
+	//The size of every response searched, after it was decompressed
+	BytesScanned    int64   `json:"bytes_scanned"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// resetMetrics clears the counts from the last run
+func (s *scanner) resetMetrics() {
+	atomic.StoreInt64(&s.requestsSent, 0)
+	atomic.StoreInt64(&s.bytesScanned, 0)
+	s.failuresMutex.Lock()
+	s.failuresByStatus = map[string]int{}
+	s.failuresMutex.Unlock()
+}
+
+// countFailure adds a URL that couldn't be fetched to failuresByStatus
+func (s *scanner) countFailure(err *fetchError) {
+	status := "error"
+	if err.StatusCode != 0 {
+		status = strconv.Itoa(err.StatusCode)
+	}
+	s.failuresMutex.Lock()
+	defer s.failuresMutex.Unlock()
+	s.failuresByStatus[status]++
+}
+
+// collectMetrics gets the metrics for the run so far
+//
+// Parameters:
+//   - findings: Every finding from the run.
+//   - duration: How long the run took.
+//
+// Returns:
+//   - Metrics
+func (s *scanner) collectMetrics(findings []Finding, duration time.Duration) Metrics {
+	metrics := Metrics{
+		Requests:         atomic.LoadInt64(&s.requestsSent),
+		URLsSearched:     atomic.LoadInt64(&s.urlsSearched),
+		URLsFailed:       atomic.LoadInt64(&s.urlsFailed),
+		FailuresByStatus: map[string]int{},
+		FindingsByType:   map[string]int{},
+		BytesScanned:     atomic.LoadInt64(&s.bytesScanned),
+		DurationSeconds:  duration.Seconds(),
+	}
+	s.failuresMutex.Lock()
+	for status, count := range s.failuresByStatus {
+		metrics.FailuresByStatus[status] = count
+	}
+	s.failuresMutex.Unlock()
+	for _, finding := range findings {
+		metrics.FindingsByType[finding.Type]++
+	}
+	return metrics
+}
+
+// writeMetrics writes the metrics to a file as JSON, replacing it if it already exists
+func writeMetrics(path string, metrics Metrics) error {
+	data, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
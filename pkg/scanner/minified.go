@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// The default patterns for spotting minified code, which are almost never all in a real string or secret
+var defaultMinifiedPatterns = []string{`function\(`, `\bvar\b`, `\breturn\b`}
+
+// compileMinifiedPatterns compiles the default minified patterns, which are known to be valid
+func compileMinifiedPatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, regexp.MustCompile(pattern))
+	}
+	return compiled
+}
+
+// configureMinified sets up the minified code filter from the Options
+//
+// Returns:
+//   - error: If a pattern doesn't compile, or the threshold is more than the number of patterns.
+func (s *scanner) configureMinified(o Options) error {
+	s.minifiedPatterns = compileMinifiedPatterns(defaultMinifiedPatterns)
+	if len(o.MinifiedPatterns) > 0 {
+		s.minifiedPatterns = nil
+		for _, pattern := range o.MinifiedPatterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid minified pattern %s: %w", pattern, err)
+			}
+			s.minifiedPatterns = append(s.minifiedPatterns, re)
+		}
+	}
+	//0 means every pattern has to match, which is how the default patterns are used
+	s.minifiedThreshold = o.MinifiedThreshold
+	if s.minifiedThreshold < 0 || s.minifiedThreshold > len(s.minifiedPatterns) {
+		return fmt.Errorf("minified-threshold must be between 0 (every pattern) and the number of minified patterns")
+	}
+	if s.minifiedThreshold == 0 {
+		s.minifiedThreshold = len(s.minifiedPatterns)
+	}
+	return nil
+}
+
+// filterMinified checks if strings and secrets that look like minified code should be dropped, which is turned off with
+// the no-minified-filter flag, which the noisy flag sets in configure
+func filterMinified(opts Options) bool {
+	return !opts.NoMinifiedFilter
+}
+
+// looksMinified checks if text is a chunk of minified code rather than a string or secret, by how many of the
+// minifiedPatterns it matches
+//
+// Parameters:
+//   - text: The string, or the whole text matched by a secret pattern.
+//
+// Returns:
+//   - bool: True if the text should be dropped, see filterMinified for whether to check.
+func (s *scanner) looksMinified(text string) bool {
+	matched := 0
+	for _, re := range s.minifiedPatterns {
+		if re.MatchString(text) {
+			matched++
+		}
+		if matched >= s.minifiedThreshold {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	netUrl "net/url"
+	"sort"
+	"strings"
+)
+
+// The most findings listed in a Slack message, the rest are only counted so the message stays readable
+const maxSlackFindings = 20
+
+// The body sent to a generic webhook, which is the metrics for the run along with every finding
+type webhookPayload struct {
+	Metrics
+	Findings []Finding `json:"findings"`
+}
+
+// The body sent to a Slack incoming webhook
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// isSlackWebhook checks if a webhook URL is a Slack incoming webhook, which needs its own message format
+func isSlackWebhook(url string) bool {
+	parsedUrl, err := netUrl.Parse(url)
+	return err == nil && strings.EqualFold(parsedUrl.Hostname(), "hooks.slack.com")
+}
+
+// slackMessage writes the text of the Slack message for a run, with the counts of each type and the first few findings
+//
+// The values of the findings are left out, so the secrets don't end up in the Slack history. Use a generic webhook or
+// the output to get them.
+//
+// Example:
+//
+//	webstrings found 3 results in 12 URLs (1 failed)
+//	• AWS Access Key ID: 1
+//	• GitHub Personal Access Token (Classic): 2
+//
+//	AWS Access Key ID at https://example.com/app.js
+func slackMessage(metrics Metrics, findings []Finding) string {
+	var message strings.Builder
+	fmt.Fprintf(&message, "webstrings found %d %s in %d %s (%d failed)\n", len(findings), plural(len(findings), "result", "results"), metrics.URLsSearched, plural(int(metrics.URLsSearched), "URL", "URLs"), metrics.URLsFailed)
+
+	types := make([]string, 0, len(metrics.FindingsByType))
+	for findingType := range metrics.FindingsByType {
+		types = append(types, findingType)
+	}
+	sort.Strings(types)
+	for _, findingType := range types {
+		fmt.Fprintf(&message, "• %s: %d\n", findingType, metrics.FindingsByType[findingType])
+	}
+
+	message.WriteString("\n")
+	for i, finding := range findings {
+		if i == maxSlackFindings {
+			fmt.Fprintf(&message, "…and %d more\n", len(findings)-maxSlackFindings)
+			break
+		}
+		fmt.Fprintf(&message, "%s at %s\n", finding.Type, finding.URL)
+	}
+	return message.String()
+}
+
+// sendWebhook posts the findings from a run to a webhook as JSON
+//
+// Slack incoming webhooks get a message with slackMessage, and anything else gets a webhookPayload.
+//
+// Parameters:
+//   - ctx: The context for the request, used to cancel it if needed.
+//   - url: The webhook URL.
+//   - metrics: The metrics for the run, see collectMetrics.
+//   - findings: Every finding from the run.
+//
+// Returns:
+//   - error: If the request fails or the webhook doesn't return a 2xx status.
+func (s *scanner) sendWebhook(ctx context.Context, url string, metrics Metrics, findings []Finding) error {
+	var payload any = webhookPayload{Metrics: metrics, Findings: findings}
+	if isSlackWebhook(url) {
+		payload = slackPayload{Text: slackMessage(metrics, findings)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status code error: %s", res.Status)
+	}
+	return nil
+}
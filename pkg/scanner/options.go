@@ -0,0 +1,669 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	netUrl "net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Options are the settings for a scan, with a field for each of the CLI flags that change how URLs are searched
+//
+// Use DefaultOptions to get the same defaults as the CLI. The Options are applied to the whole package when a scan
+// starts, so scans with different Options can't run at the same time.
+type Options struct {
+	//Search the rendered DOM with a headless browser rather than the response
+	DOM bool
+	//Search for secrets rather than strings
+	Secrets bool
+	//Also search for URLs in secrets mode
+	URLs bool
+	//Also search for private IP addresses and internal hostnames in secrets mode, which URLs does as well
+	Internal bool
+	//Report missing and weak Content-Security-Policy, Strict-Transport-Security and X-Frame-Options headers on pages
+	SecurityHeaders bool
+	//Include the secret patterns that produce a lot of false positives, along with tokens that fail their checksum
+	ExtraPatterns bool
+	//Use the URL pattern that doesn't need http(s)://, which produces a lot of false positives
+	LooseURLs bool
+	//Everything that produces a lot of false positives, the same as ExtraPatterns, LooseURLs and NoMinifiedFilter
+	Noisy bool
+	//Only search for secrets with a description containing one of these, ignoring case, like "aws" or "github"
+	Types []string
+	//Check secrets against the provider's API to see if they're active
+	Verify bool
+	//Include the URL each finding was found at in the text output
+	Location bool
+	//Report whether each finding was in the page body, an inline script or an external script as its Source, and
+	//include the Source in the text output
+	Attribution bool
+	//Report strings with a Shannon entropy at or above EntropyThreshold in secrets mode
+	Entropy          bool
+	EntropyThreshold float64
+	//Decode escapes in strings, and keep template literals whole rather than splitting out the interpolations
+	Unescape     bool
+	RawTemplates bool
+	//Length limits for strings, a MaxLength of 0 means no maximum
+	MinLength int
+	MaxLength int
+	//Regex patterns to drop findings that match, or only keep findings that match
+	Exclude []string
+	Include []string
+	//Regex patterns for spotting minified code, and how many a string or secret has to match to be dropped. The default
+	//patterns are used if there are none, and a MinifiedThreshold of 0 means every pattern has to match.
+	MinifiedPatterns  []string
+	MinifiedThreshold int
+	//Keep strings and secrets that look like minified code
+	NoMinifiedFilter bool
+	//Report URLs that couldn't be fetched as findings
+	ShowErrors bool
+	//Don't color the text output, even when it's a terminal
+	NoColor bool
+	//Triage the findings in a TUI once the run is finished, with false positives added to IgnoreFile
+	TUI bool
+	//Where to write the metrics for the run as JSON, empty to not write them
+	MetricsFile string
+	//File to keep the URLs that have been searched in, so an interrupted run can be resumed by running it again
+	StateFile string
+	//File of findings from earlier runs that aren't reported again, the new findings are added to it at the end of a run
+	Baseline string
+	//Compare findings to the Baseline by URL as well as type and value, so a secret found somewhere new is reported
+	BaselineURLs bool
+	//Webhook to POST the findings to as JSON at the end of a run with any findings, Slack webhooks get a message
+	NotifyWebhook string
+	//File of findings to ignore, .webstringsignore is used if it's empty and the file exists
+	IgnoreFile string
+	//Stop the run once this many findings have been reported, 0 for no limit
+	Limit int
+	//The lowest severity of findings to report, info, low, medium, high or critical. Empty reports everything.
+	MinSeverity string
+	//Count how many times each finding was found in a URL
+	Count bool
+	//Search URLs again even if they've already been searched
+	NoDedupe bool
+	//Search bodies that are the same as one already searched at a different URL, which NoDedupe does as well
+	NoContentDedupe bool
+	//Format of the output, text, csv, json, ndjson or html
+	Format string
+	//Requests per second to each host, 0 for no limit, and how many requests can be sent at once before the rate applies
+	Rate  float64
+	Burst int
+	//The most random delay to add before each request on top of the Rate, 0 for none
+	Jitter time.Duration
+	//The maximum number of URLs to search at the same time
+	Concurrency int
+	//HTTP method and body to send, the method defaults to GET, or POST if there's Data
+	Method string
+	Data   string
+	//Send an introspection query to GraphQL endpoints and search the schema
+	GraphQL bool
+	//User-Agent and extra headers in the format "Name: Value" to send with each request
+	UserAgent string
+	Headers   []string
+	//Headers to send with specific URLs on top of Headers, like the ones captured with each request in a HAR file
+	//from LoadHAR
+	ReplayHeaders map[string]http.Header
+	//Authorization header to send, either a bearer token or the whole header value
+	Bearer     string
+	AuthHeader string
+	//Proxy to send requests through, like http://127.0.0.1:8080
+	Proxy string
+	//Domains to search, which support wildcards for subdomains. Defaults to the domains of the input URLs.
+	Scope []string
+	//Cookies to send in the format "name=value", and a Netscape or JSON cookie file to load
+	Cookies    []string
+	CookieFile string
+	//A script of browser actions to log in with before DOM searches, see loadLoginScript
+	LoginScript string
+	//A login page to fill in the username and password on before DOM searches, instead of a LoginScript
+	LoginURL      string
+	LoginUser     string
+	LoginPassword string
+	//Skip TLS certificate verification
+	Insecure bool
+	//Characters of surrounding text to include with each secret
+	Context int
+	//Log the details of each request, and leave out the progress messages
+	Verbose bool
+	Quiet   bool
+	//Search base64 strings for secrets
+	DecodeBase64 bool
+	//List the URLs that would be searched rather than searching them
+	DryRun bool
+	//Fetch the scripts on each page while the page is searched, and search the sourcemaps of scripts
+	FetchScripts bool
+	Sourcemaps   bool
+	//Only search the page itself, without finding and queueing the scripts on it
+	NoScripts bool
+	//Search URLs disallowed by robots.txt
+	IgnoreRobots bool
+	//Also search the pages listed in the sitemap.xml of each input URL's site
+	Sitemap bool
+	//Paths to try on the site of each input URL, like /app.js or /.env, and whether to try the built-in list of common
+	//paths as well
+	Paths       []string
+	CommonPaths bool
+	//Timeout for each request and DOM search
+	Timeout time.Duration
+	//How long each secret pattern can take on a response before it's skipped, 0 for no limit
+	RegexTimeout time.Duration
+	//A CSS selector to wait for, and how long the network has to be idle, before searching the DOM
+	WaitSelector string
+	WaitIdle     time.Duration
+	//Content-Types of the responses to search, and the most bytes of each response to read
+	ContentTypes []string
+	MaxSize      int64
+	//Times to retry a request after a network error, 429 or 5xx response
+	Retries int
+	//Don't follow redirects, and the most redirects to follow for each request otherwise
+	NoRedirect   bool
+	MaxRedirects int
+	//Levels of discovered scripts to search, 0 only searches the input URLs
+	Depth int
+	//Where findings are written as they're found, and where progress messages and the summary are written. Nothing
+	//is written if they're nil, since Scan returns the findings.
+	Output   io.Writer
+	Progress io.Writer
+}
+
+// DefaultOptions gets the Options with the same defaults as the CLI, which searches for strings
+func DefaultOptions() Options {
+	return Options{
+		EntropyThreshold: 4.5,
+		MinLength:        4,
+		Format:           "text",
+		Rate:             1,
+		Burst:            1,
+		Concurrency:      10,
+		UserAgent:        defaultUserAgent,
+		Timeout:          defaultRequestTimeout,
+		RegexTimeout:     defaultRegexTimeout,
+		ContentTypes:     defaultContentTypes,
+		MaxSize:          defaultMaxResponseSize,
+		MaxRedirects:     defaultMaxRedirects,
+		Depth:            1,
+	}
+}
+
+// configure checks the Options and creates the scanner for a run or library call from them, before anything is searched
+//
+// Parameters:
+//   - o: The Options for the scan, the settings that Noisy turns on are set in it.
+//
+// Returns:
+//   - *scanner: The scanner with the settings from the Options.
+//   - *hostLimiter: The rate limiter for the scan, see newHostLimiter.
+//   - error: If any of the Options are invalid.
+func configure(o *Options) (*scanner, *hostLimiter, error) {
+	s := newScanner()
+	//The noisy flag turns on each of the noisier settings, so the rest of the scanner only checks those
+	if o.Noisy {
+		o.ExtraPatterns, o.LooseURLs, o.NoMinifiedFilter = true, true, true
+	}
+	if !o.Secrets && o.URLs {
+		fmt.Fprintln(os.Stderr, "URLS flag is only available in secrets mode, continuing with only strings")
+	}
+	if !o.Secrets && o.Internal {
+		fmt.Fprintln(os.Stderr, "Internal flag is only available in secrets mode, continuing with only strings")
+	}
+
+	s.secretTypes = o.Types
+	if !o.Secrets && len(s.secretTypes) > 0 {
+		fmt.Fprintln(os.Stderr, "Types flag is only available in secrets mode, continuing with only strings")
+	}
+	s.compiledSecretRegex = s.compileSecretRegex(*o)
+	s.regexTimeout = o.RegexTimeout
+	if s.regexTimeout < 0 {
+		return nil, nil, fmt.Errorf("regex-timeout must be 0 or greater")
+	}
+	for _, secretType := range s.secretTypes {
+		if !anySecretMatches(secretType) {
+			//Non-breaking, the other types are still searched for
+			fmt.Fprintf(os.Stderr, "Warning - No secret types match %q, see --list-types\n", secretType)
+		}
+	}
+
+	if o.Rate < 0 {
+		return nil, nil, fmt.Errorf("rate must be 0 (unlimited) or greater")
+	}
+	if o.Burst < 1 {
+		return nil, nil, fmt.Errorf("burst must be at least 1")
+	}
+
+	if !o.Secrets && o.Entropy {
+		fmt.Fprintln(os.Stderr, "Entropy flag is only available in secrets mode, continuing with only strings")
+	}
+	s.entropyThreshold = o.EntropyThreshold
+
+	s.minStringLength = o.MinLength
+	s.maxStringLength = o.MaxLength
+	if s.maxStringLength < 0 || (s.maxStringLength > 0 && s.maxStringLength < s.minStringLength) {
+		return nil, nil, fmt.Errorf("max-length must be 0 (no maximum) or at least min-length")
+	}
+
+	var err error
+	s.includeFilters, err = compileFilters(o.Include)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.excludeFilters, err = compileFilters(o.Exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.configureMinified(*o)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.configureBaseline(*o)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.configureSeverity(*o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.IgnoreFile != "" {
+		s.ignoreFilePath = o.IgnoreFile
+		s.ignoredFindings, err = loadIgnoreFile(o.IgnoreFile)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if _, statErr := os.Stat(defaultIgnoreFile); statErr == nil {
+		s.ignoredFindings, err = loadIgnoreFile(defaultIgnoreFile)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	//Like curl, sending data without choosing a method sends a POST
+	s.requestData = o.Data
+	s.requestMethod = strings.ToUpper(o.Method)
+	if s.requestMethod == "" {
+		s.requestMethod = "GET"
+		if s.requestData != "" {
+			s.requestMethod = "POST"
+		}
+	}
+	if !validMethod(s.requestMethod) {
+		return nil, nil, fmt.Errorf("invalid method %s", o.Method)
+	}
+	if o.GraphQL {
+		if s.requestData != "" {
+			return nil, nil, fmt.Errorf("the graphql and data flags can't be used together")
+		}
+		s.requestMethod = "POST"
+		s.requestData = graphqlRequestBody()
+	}
+	if o.DOM && s.requestMethod != "GET" {
+		//Non-breaking, the browser always navigates with a GET
+		fmt.Fprintf(os.Stderr, "Warning - The method and data flags aren't used for DOM searches\n")
+	}
+
+	s.userAgent = o.UserAgent
+	headers, err := parseHeaders(o.Headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	//Set over any Authorization header from the header flag. The token is never logged, even with the verbose flag.
+	auth, err := authorizationHeader(o.Bearer, o.AuthHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if auth != "" {
+		headers.Set("Authorization", auth)
+	}
+	s.requestHeaders = headers
+	s.replayHeaders = map[string]http.Header{}
+	for url, header := range o.ReplayHeaders {
+		s.replayHeaders[normalizeURL(addScheme(url))] = header
+	}
+
+	s.contextLength = o.Context
+	if s.contextLength < 0 {
+		return nil, nil, fmt.Errorf("context must be 0 or greater")
+	}
+
+	s.maxRetries = o.Retries
+	if s.maxRetries < 0 {
+		return nil, nil, fmt.Errorf("retries must be 0 or greater")
+	}
+
+	s.contentTypes = o.ContentTypes
+
+	s.maxResponseSize = o.MaxSize
+	if s.maxResponseSize <= 0 {
+		return nil, nil, fmt.Errorf("max-size must be greater than 0")
+	}
+
+	s.requestTimeout = o.Timeout
+	s.domWaitSelector = o.WaitSelector
+	s.domWaitIdle = o.WaitIdle
+	if s.domWaitIdle < 0 {
+		return nil, nil, fmt.Errorf("wait-idle must be 0 or greater")
+	}
+	if !o.DOM && (s.domWaitSelector != "" || s.domWaitIdle > 0) {
+		//Non-breaking, there's nothing to wait for without the browser
+		fmt.Fprintf(os.Stderr, "Warning - The wait-selector and wait-idle flags are only used for DOM searches\n")
+	}
+	s.proxyServer = o.Proxy
+	s.insecureTLS = o.Insecure
+	s.httpClient, err = newHTTPClient(s.requestTimeout, s.proxyServer, s.insecureTLS)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.MaxRedirects < 0 {
+		return nil, nil, fmt.Errorf("max-redirects must be 0 or greater")
+	}
+	s.httpClient.CheckRedirect = s.redirectPolicy(!o.NoRedirect, o.MaxRedirects)
+
+	s.requestCookies, err = parseCookies(o.Cookies)
+	if err != nil {
+		return nil, nil, err
+	}
+	var fileCookies []*http.Cookie
+	if o.CookieFile != "" {
+		fileCookies, err = loadCookieFile(o.CookieFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.requestCookies = append(s.requestCookies, fileCookies...)
+	}
+	s.httpClient.Jar, err = newCookieJar(fileCookies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if (o.LoginScript != "" || o.LoginURL != "") && !o.DOM {
+		return nil, nil, fmt.Errorf("the login-script and login-url flags need the dom flag")
+	}
+	if o.LoginScript != "" && o.LoginURL != "" {
+		return nil, nil, fmt.Errorf("the login-script and login-url flags can't be used together")
+	}
+	if o.LoginScript != "" {
+		s.loginSteps, err = loadLoginScript(o.LoginScript)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if o.LoginURL != "" {
+		if o.LoginUser == "" || o.LoginPassword == "" {
+			return nil, nil, fmt.Errorf("login-url needs login-user and login-pass")
+		}
+		s.loginSteps = formLoginSteps(o.LoginURL, o.LoginUser, o.LoginPassword)
+	}
+
+	if o.Concurrency < 1 {
+		return nil, nil, fmt.Errorf("concurrency must be at least 1")
+	}
+	if o.Depth < 0 {
+		return nil, nil, fmt.Errorf("depth must be 0 or greater")
+	}
+
+	s.verboseLog.SetOutput(io.Discard)
+	if o.Verbose {
+		s.verboseLog.SetOutput(os.Stderr)
+	}
+
+	if o.Format != "text" && o.Format != "csv" && o.Format != "json" && o.Format != "ndjson" && o.Format != "html" {
+		return nil, nil, fmt.Errorf("format must be text, csv, json, ndjson or html")
+	}
+	s.outputFormat = o.Format
+
+	s.metricsPath = o.MetricsFile
+	s.statePath = o.StateFile
+	if o.Limit < 0 {
+		return nil, nil, fmt.Errorf("limit must be 0 or greater")
+	}
+	s.findingLimit = int64(o.Limit)
+	s.webhookURL = o.NotifyWebhook
+	if s.webhookURL != "" {
+		parsedUrl, err := netUrl.Parse(s.webhookURL)
+		if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			return nil, nil, fmt.Errorf("notify-webhook must be an http or https URL")
+		}
+	}
+	if o.NoScripts && o.FetchScripts {
+		return nil, nil, fmt.Errorf("the no-scripts and fetch-scripts flags can't be used together")
+	}
+	s.tuiMode = o.TUI
+	if s.tuiMode && o.DryRun {
+		return nil, nil, fmt.Errorf("the tui and dry-run flags can't be used together")
+	}
+
+	s.output = io.Discard
+	if o.Output != nil {
+		s.output = o.Output
+	}
+	s.progress = io.Discard
+	if o.Progress != nil {
+		s.progress = o.Progress
+	}
+	//Files and pipes get plain text, so the escape codes don't end up in them
+	s.colorOutput = s.outputFormat == "text" && !o.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(s.output)
+
+	if o.Jitter < 0 {
+		return nil, nil, fmt.Errorf("jitter must be 0 or greater")
+	}
+	//Limit the number of requests per second to each host, shared across all of the goroutines in run
+	limiter := newHostLimiter(o.Rate, o.Burst)
+	limiter.jitter = o.Jitter
+	return s, limiter, nil
+}
+
+// Run searches URLs for strings or secrets, along with the scripts they load up to the Depth in the Options
+//
+// Findings are written to the Output in the Format from the Options, and a summary is written to the Progress once the
+// run is finished.
+//
+// Parameters:
+//   - ctx: The context for the run, cancelling it stops any searches that haven't finished. The findings from the
+//     searches that did finish are still written and returned, along with the ctx error.
+//   - urls: The URLs to search, https:// is added to any without a scheme.
+//   - o: The Options for the run.
+//
+// Returns:
+//   - []Finding: Every finding from the run.
+//   - error: If the Options are invalid, or something went wrong that stopped the run. URLs that couldn't be fetched
+//     are reported with a warning instead.
+func Run(ctx context.Context, urls []string, o Options) ([]Finding, error) {
+	s, limiter, err := configure(&o)
+	if err != nil {
+		return nil, err
+	}
+	urlQueue, err := s.prepare(ctx, urls, o, limiter)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, urlQueue, o, limiter)
+}
+
+// Stream searches URLs like Run, but sends each finding on a channel as soon as it's found rather than returning them
+// once the run is finished, for showing findings live or passing them on to something else like a server
+//
+// With TUI set, the findings are sent once they've been triaged instead, leaving out the false positives.
+//
+// Example:
+//
+//	findings, errs := scanner.Stream(ctx, urls, opts)
+//	for finding := range findings {
+//		fmt.Println(finding.Type, finding.Value)
+//	}
+//	if err := <-errs; err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - ctx: The context for the run, cancelling it stops any searches that haven't finished.
+//   - urls: The URLs to search, https:// is added to any without a scheme.
+//   - o: The Options for the run.
+//
+// Returns:
+//   - <-chan Finding: The findings, closed once the run is finished. It needs to be read until then, or the ctx
+//     cancelled, since the searches wait for each finding to be read.
+//   - <-chan error: The error from the run like Run returns, or nil, sent once the findings are closed.
+func Stream(ctx context.Context, urls []string, o Options) (<-chan Finding, <-chan error) {
+	findings := make(chan Finding)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(findings)
+		s, limiter, err := configure(&o)
+		if err != nil {
+			errs <- err
+			return
+		}
+		urlQueue, err := s.prepare(ctx, urls, o, limiter)
+		if err != nil {
+			errs <- err
+			return
+		}
+		s.findingsStream, s.findingsStreamDone = findings, ctx.Done()
+		_, err = s.run(ctx, urlQueue, o, limiter)
+		errs <- err
+	}()
+	return findings, errs
+}
+
+// prepare queues the input URLs for Run and Stream once the scanner is configured, along with the pages from their
+// sitemaps if Sitemap is set and the Paths on their sites. If there are login steps, the browser logs in first.
+//
+// Parameters:
+//   - ctx: The context for the run.
+//   - urls: The input URLs.
+//   - o: The Options from configure.
+//   - limiter: The rate limiter from configure, used for the sitemaps.
+//
+// Returns:
+//   - *URLQueue: The queue with the input URLs.
+//   - error: If logging in fails, or one of the URLs can't be parsed.
+func (s *scanner) prepare(ctx context.Context, urls []string, o Options, limiter *hostLimiter) (*URLQueue, error) {
+	//Logged in once for the whole run, and the session cookies are sent with every DOM search and request after it
+	if len(s.loginSteps) > 0 {
+		cookies, err := s.browserLogin(ctx, s.loginSteps)
+		if err != nil {
+			return nil, err
+		}
+		s.verboseLog.Printf("Logged in, got %d cookies", len(cookies))
+		s.requestCookies = append(s.requestCookies, cookies...)
+		var jarCookies []*http.Cookie
+		for _, cookie := range s.requestCookies {
+			if cookie.Domain != "" {
+				jarCookies = append(jarCookies, cookie)
+			}
+		}
+		s.httpClient.Jar, err = newCookieJar(jarCookies)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	urlQueue := &URLQueue{Duplicates: o.NoDedupe}
+	s.inputURLs = map[string]struct{}{}
+	for _, url := range urls {
+		url = addScheme(url)
+		_, err := netUrl.Parse(url)
+		if err != nil {
+			return nil, err
+		}
+		s.inputURLs[normalizeURL(url)] = struct{}{}
+		urlQueue.Push(url)
+	}
+
+	s.crawlScope = o.Scope
+	if len(s.crawlScope) == 0 {
+		s.crawlScope = seedScope(urlQueue.queue)
+	}
+
+	//Queued after the scope is set, so a sitemap listing other sites doesn't widen the scope
+	if o.Sitemap {
+		for _, page := range s.sitemapPages(ctx, urls, limiter) {
+			if !s.inScope(page) {
+				fmt.Fprintf(os.Stderr, "Warning - Skipping %s, out of scope\n", page)
+				continue
+			}
+			urlQueue.Push(page)
+		}
+	}
+	s.guessedURLs = map[string]struct{}{}
+	for _, guess := range pathURLs(urls, searchPaths(o)) {
+		if !s.inScope(guess) {
+			fmt.Fprintf(os.Stderr, "Warning - Skipping %s, out of scope\n", guess)
+			continue
+		}
+		s.guessedURLs[normalizeURL(guess)] = struct{}{}
+		urlQueue.Push(guess)
+	}
+	return urlQueue, nil
+}
+
+// Scan searches a URL for strings or secrets, along with the scripts it loads up to the Depth in the Options
+//
+// Example:
+//
+//	opts := scanner.DefaultOptions()
+//	opts.Secrets = true
+//	findings, err := scanner.Scan(ctx, "https://example.com", opts)
+//
+// See Run for more details.
+func Scan(ctx context.Context, url string, o Options) ([]Finding, error) {
+	return Run(ctx, []string{url}, o)
+}
+
+// GetContents gets the contents of a URL, using the request settings from the Options like the Headers and Timeout
+//
+// Returns:
+//   - string: The response body, empty if its Content-Type isn't in the ContentTypes from the Options.
+//   - string: The Content-Type header of the response.
+//   - error
+func GetContents(ctx context.Context, url string, o Options) (string, string, error) {
+	s, _, err := configure(&o)
+	if err != nil {
+		return "", "", err
+	}
+	text, contentType, err := s.getContents(ctx, addScheme(url), "")
+	if err != nil || text == nil {
+		return "", contentType, err
+	}
+	return *text, contentType, nil
+}
+
+// GetScripts gets the script source links from an HTML page, like /app.js
+func GetScripts(html string) ([]string, error) {
+	return getScripts(&html)
+}
+
+// GetStrings gets the strings in JavaScript code, using the string settings from the Options like MinLength
+func GetStrings(text string, o Options) ([]string, error) {
+	s, _, err := configure(&o)
+	if err != nil {
+		return nil, err
+	}
+	return s.getStrings(text, o)
+}
+
+// GetSecrets gets the secrets in text, using the secret settings from the Options like Types and Noisy
+//
+// Returns:
+//   - []Finding: The secrets found, without a URL or Source.
+//   - error: If the Options are invalid.
+func GetSecrets(text string, o Options) ([]Finding, error) {
+	s, _, err := configure(&o)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for description, matches := range s.getSecrets(text, o) {
+		for _, match := range matches {
+			findings = append(findings, Finding{Type: description, Value: match.Value, Context: match.Context, Encoding: match.Encoding, Details: match.Details, Line: match.Line, Column: match.Column})
+		}
+	}
+	//Stable so the matches of each type stay in the order they were found in
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Type < findings[j].Type })
+	return findings, nil
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	netUrl "net/url"
+	"strings"
+)
+
+// The paths tried on each input site with the common-paths flag, where bundles and config files are often left exposed
+var defaultPaths = []string{
+	"/main.js",
+	"/app.js",
+	"/bundle.js",
+	"/index.js",
+	"/vendor.js",
+	"/runtime.js",
+	"/config.js",
+	"/env.js",
+	"/static/js/main.js",
+	"/js/app.js",
+	"/assets/index.js",
+	"/.env",
+	"/.env.local",
+	"/.env.production",
+	"/.env.development",
+	"/config.json",
+	"/env.json",
+	"/settings.json",
+	"/appsettings.json",
+	"/asset-manifest.json",
+}
+
+// searchPaths gets the paths to try on each input site from the Options
+//
+// Returns:
+//   - []string: The defaultPaths if CommonPaths is set, followed by the Paths.
+func searchPaths(o Options) []string {
+	var paths []string
+	if o.CommonPaths {
+		paths = append(paths, defaultPaths...)
+	}
+	return append(paths, o.Paths...)
+}
+
+// pathURLs combines the site of each input URL with each path, so well-known files can be searched even when nothing
+// links to them
+//
+// Parameters:
+//   - urls: The input URLs, only their scheme and host are used.
+//   - paths: The paths to try, a / is added to the start of any that don't have one.
+//
+// Returns:
+//   - []string: A URL for each site and path, in the order of the sites and without duplicates.
+func pathURLs(urls []string, paths []string) []string {
+	var guesses []string
+	seen := map[string]struct{}{}
+	for _, url := range urls {
+		parsedUrl, err := netUrl.Parse(addScheme(url))
+		if err != nil || parsedUrl.Host == "" {
+			continue
+		}
+		origin := parsedUrl.Scheme + "://" + parsedUrl.Host
+		for _, path := range paths {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			if _, ok := seen[origin+path]; ok {
+				continue
+			}
+			seen[origin+path] = struct{}{}
+			guesses = append(guesses, origin+path)
+		}
+	}
+	return guesses
+}
+
+// isGuessedURL checks if a URL was queued by pathURLs
+func (s *scanner) isGuessedURL(url string) bool {
+	_, ok := s.guessedURLs[normalizeURL(url)]
+	return ok
+}
@@ -0,0 +1,250 @@
+package scanner
+
+import (
+	"context"
+	netUrl "net/url"
+	"strings"
+	"sync"
+)
+
+// A URLQueue is the queue of URLs to search, which the search goroutines push the scripts they find onto
+//
+// It's safe to Push, Pop and Next from any number of goroutines at the same time. Next waits for a URL to be pushed if
+// the queue is empty, until the queue is closed with Close. Each URL from Next is marked as finished with Done, and once
+// every URL pushed is finished the queue is closed, since nothing is left that could push more.
+type URLQueue struct {
+	mu    sync.Mutex
+	queue []string
+	//The depth of each URL in the queue, see PushAt
+	depths []int
+	//The shallowest depth each URL has been pushed at
+	seen map[string]int
+	//The URLs that are queued or haven't been marked as Done yet
+	pending int
+	// If Duplicates is true, Push will queue URLs even if they have been pushed before
+	Duplicates bool
+	//Signalled when a URL is pushed, to wake up a goroutine waiting in Next
+	ready chan struct{}
+	//Closed by Close, to wake up every goroutine waiting in Next
+	done   chan struct{}
+	closed bool
+}
+
+// init creates the channels for Next and Close, so the zero value of a URLQueue is ready to use. q.mu must be held.
+func (q *URLQueue) init() {
+	if q.ready == nil {
+		q.ready = make(chan struct{}, 1)
+		q.done = make(chan struct{})
+	}
+}
+
+// signal wakes up a goroutine waiting in Next, if there are any. q.mu must be held.
+func (q *URLQueue) signal() {
+	q.init()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+		//One is already waiting to be woken up, and it signals the next one if there are more URLs
+	}
+}
+
+// Push adds an input URL to the queue, at depth 0, see PushAt
+func (q *URLQueue) Push(url string) bool {
+	return q.PushAt(url, 0)
+}
+
+// PushAt adds a URL to the queue, unless it is empty, the queue is closed, or it has already been pushed at the same
+// depth or a shallower one and Duplicates is false
+//
+// The URL is normalized with normalizeURL first, so the same page written two ways is only searched once. A URL pushed
+// again at a shallower depth is queued again, since a URL first found past the max depth isn't searched, and one found
+// closer to the input URLs has more of the scripts it leads to in range.
+//
+// Parameters:
+//   - url: The URL to queue.
+//   - depth: How many levels of discovered scripts the URL is past the input URLs, one more than the page it was found on.
+//
+// Returns:
+//   - bool: True if the URL was queued.
+func (q *URLQueue) PushAt(url string, depth int) bool {
+	if url == "" {
+		return false
+	}
+	url = normalizeURL(url)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return false
+	}
+	if !q.see(url, depth) {
+		return false
+	}
+	q.queue = append(q.queue, url)
+	q.depths = append(q.depths, depth)
+	q.pending++
+	q.signal()
+	return true
+}
+
+// MarkSeen marks a URL as seen at a depth without queueing it, for URLs that are searched another way like the scripts
+// fetched with a page by fetchScripts, so they aren't fetched again from another page or pushed later
+//
+// Returns:
+//   - bool: True if the URL hadn't been seen at the same depth or a shallower one before, or Duplicates is true.
+func (q *URLQueue) MarkSeen(url string, depth int) bool {
+	if url == "" {
+		return false
+	}
+	url = normalizeURL(url)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.see(url, depth)
+}
+
+// see marks a normalized URL as seen at a depth, and returns false if it already was at the same depth or a shallower one
+// and Duplicates is false. q.mu must be held.
+func (q *URLQueue) see(url string, depth int) bool {
+	if q.Duplicates {
+		return true
+	}
+	if q.seen == nil {
+		q.seen = map[string]int{}
+	}
+	if seenDepth, ok := q.seen[url]; ok && seenDepth <= depth {
+		return false
+	}
+	q.seen[url] = depth
+	return true
+}
+
+// normalizeURL rewrites a URL in a canonical form, so that URLs that request the same thing are the same string
+//
+// The host is lowercased, default ports (80 for http and 443 for https) are removed, the fragment is removed since it
+// isn't sent to the server, and an empty path becomes /, so https://Example.com:443#top is https://example.com/. Other
+// trailing slashes are kept, since /docs and /docs/ can be different pages.
+//
+// Parameters:
+//   - url: The URL to normalize.
+//
+// Returns:
+//   - string: The normalized URL, or the URL unchanged if it can't be parsed or doesn't have a host.
+func normalizeURL(url string) string {
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil || parsedUrl.Host == "" {
+		return url
+	}
+	host := strings.ToLower(parsedUrl.Hostname())
+	//IPv6 addresses need their brackets back once they're separated from the port
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := parsedUrl.Port()
+	if (parsedUrl.Scheme == "http" && port == "80") || (parsedUrl.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	parsedUrl.Host = host
+	parsedUrl.Fragment = ""
+	parsedUrl.RawFragment = ""
+	if parsedUrl.Path == "" && parsedUrl.RawPath == "" {
+		parsedUrl.Path = "/"
+	}
+	return parsedUrl.String()
+}
+
+// Pop removes the next URL from the queue without waiting, the bool is false if the queue is empty
+func (q *URLQueue) Pop() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	url, _, ok := q.pop()
+	return url, ok
+}
+
+// pop removes the next URL and its depth from the queue. q.mu must be held.
+func (q *URLQueue) pop() (string, int, bool) {
+	if len(q.queue) == 0 {
+		return "", 0, false
+	}
+	url, depth := q.queue[0], q.depths[0]
+	q.queue, q.depths = q.queue[1:], q.depths[1:]
+	//Pass the signal on, since a single Push only wakes up one goroutine
+	if len(q.queue) > 0 {
+		q.signal()
+	}
+	return url, depth, true
+}
+
+// Next removes the next URL from the queue, waiting for one to be pushed if the queue is empty
+//
+// Parameters:
+//   - ctx: Stops waiting when it's cancelled.
+//
+// Returns:
+//   - string: The next URL.
+//   - int: The depth it was pushed at.
+//   - bool: False if the queue was closed and every URL in it was removed, or the ctx was cancelled.
+func (q *URLQueue) Next(ctx context.Context) (string, int, bool) {
+	for {
+		q.mu.Lock()
+		q.init()
+		url, depth, ok := q.pop()
+		closed := q.closed
+		ready, done := q.ready, q.done
+		q.mu.Unlock()
+		if ok {
+			return url, depth, true
+		}
+		if closed {
+			return "", 0, false
+		}
+
+		select {
+		case <-ready:
+		case <-done:
+		case <-ctx.Done():
+			return "", 0, false
+		}
+	}
+}
+
+// Done marks a URL from Next as finished, after anything found while searching it has been pushed. Once every URL
+// pushed is finished, the queue is closed.
+func (q *URLQueue) Done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending--
+	if q.pending <= 0 && !q.closed {
+		q.init()
+		q.closed = true
+		close(q.done)
+	}
+}
+
+// Close stops any more URLs from being pushed. The URLs already in the queue can still be removed, and then Next
+// returns false rather than waiting.
+func (q *URLQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.init()
+	q.closed = true
+	close(q.done)
+}
+
+// Queued gets a copy of the URLs waiting in the queue, along with the depth of each one
+func (q *URLQueue) Queued() ([]string, []int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]string(nil), q.queue...), append([]int(nil), q.depths...)
+}
+
+// Len is the number of URLs waiting in the queue
+func (q *URLQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queue)
+}
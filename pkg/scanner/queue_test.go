@@ -0,0 +1,180 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLQueue(t *testing.T) {
+	//Test case: Duplicate URLs are ignored
+	urlQueue := &URLQueue{}
+	urlQueue.Push("https://example.com/app.js")
+	urlQueue.Push("https://example.com/vendor.js")
+	urlQueue.Push("https://example.com/app.js")
+	var urls []string
+	for url, ok := urlQueue.Pop(); ok; url, ok = urlQueue.Pop() {
+		urls = append(urls, url)
+	}
+	assert.Equal(t, []string{"https://example.com/app.js", "https://example.com/vendor.js"}, urls, "Expected duplicate URL to be ignored")
+
+	//Test case: URLs that were already popped are still ignored
+	urlQueue.Push("https://example.com/app.js")
+	_, ok := urlQueue.Pop()
+	assert.False(t, ok, "Expected previously popped URL to be ignored")
+
+	//Test case: URLs pushed past the max depth can be pushed again at a shallower depth, but not a deeper one
+	urlQueue = &URLQueue{}
+	assert.True(t, urlQueue.PushAt("https://example.com/deep.js", 3), "Expected the URL to be queued")
+	assert.True(t, urlQueue.PushAt("https://example.com/deep.js", 1), "Expected the URL to be queued at a shallower depth")
+	assert.False(t, urlQueue.PushAt("https://example.com/deep.js", 2), "Expected the URL not to be queued at a deeper depth")
+	assert.False(t, urlQueue.PushAt("https://example.com/deep.js", 1), "Expected the URL not to be queued at the same depth")
+	assert.Equal(t, []int{3, 1}, urlQueue.depths, "Unexpected depths")
+
+	//Test case: Duplicates allowed
+	urlQueue = &URLQueue{Duplicates: true}
+	urlQueue.Push("https://example.com/app.js")
+	urlQueue.Push("https://example.com/app.js")
+	urls = nil
+	for url, ok := urlQueue.Pop(); ok; url, ok = urlQueue.Pop() {
+		urls = append(urls, url)
+	}
+	assert.Equal(t, []string{"https://example.com/app.js", "https://example.com/app.js"}, urls, "Expected duplicate URL to be queued")
+}
+
+func TestURLQueueNext(t *testing.T) {
+	//Test case: Next waits for a URL to be pushed
+	urlQueue := &URLQueue{}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		urlQueue.Push("https://example.com/app.js")
+	}()
+	url, _, ok := urlQueue.Next(context.Background())
+	assert.True(t, ok, "Expected a URL")
+	assert.Equal(t, "https://example.com/app.js", url, "Unexpected URL")
+
+	//Test case: URLs pushed before closing can still be removed, then Next stops waiting
+	urlQueue.Push("https://example.com/vendor.js")
+	urlQueue.Close()
+	assert.False(t, urlQueue.Push("https://example.com/late.js"), "Expected pushing to a closed queue to be ignored")
+	url, _, ok = urlQueue.Next(context.Background())
+	assert.True(t, ok, "Expected the URL pushed before closing")
+	assert.Equal(t, "https://example.com/vendor.js", url, "Unexpected URL")
+	_, _, ok = urlQueue.Next(context.Background())
+	assert.False(t, ok, "Expected a closed and empty queue to stop waiting")
+
+	//Test case: Cancelling the context stops waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, ok = (&URLQueue{}).Next(ctx)
+	assert.False(t, ok, "Expected a cancelled context to stop waiting")
+}
+
+func TestURLQueueConcurrent(t *testing.T) {
+	//Test case: Every URL pushed from many goroutines is removed exactly once, run with -race to check for data races
+	urlQueue := &URLQueue{}
+	const pushers = 8
+	const urlsEach = 200
+	var received sync.Map
+	var consumers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for url, _, ok := urlQueue.Next(context.Background()); ok; url, _, ok = urlQueue.Next(context.Background()) {
+				_, loaded := received.LoadOrStore(url, struct{}{})
+				assert.False(t, loaded, "Expected each URL to be removed once")
+			}
+		}()
+	}
+	var pushed sync.WaitGroup
+	for i := 0; i < pushers; i++ {
+		pushed.Add(1)
+		go func(i int) {
+			defer pushed.Done()
+			for j := 0; j < urlsEach; j++ {
+				urlQueue.Push(fmt.Sprintf("https://example.com/%d/%d.js", i, j))
+				//Pushed again, like the same script found on two pages
+				urlQueue.Push(fmt.Sprintf("https://example.com/%d/%d.js", i, j))
+				//Removed without waiting at the same time as the goroutines waiting in Next
+				if url, ok := urlQueue.Pop(); ok {
+					_, loaded := received.LoadOrStore(url, struct{}{})
+					assert.False(t, loaded, "Expected each URL to be removed once")
+				}
+			}
+		}(i)
+	}
+	pushed.Wait()
+	urlQueue.Close()
+	consumers.Wait()
+
+	count := 0
+	received.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 0, urlQueue.Len(), "Expected the queue to be empty")
+	assert.Equal(t, pushers*urlsEach, count, "Expected every URL to be removed once, and the duplicates to be ignored")
+}
+
+func TestURLQueueDone(t *testing.T) {
+	//Test case: The queue stays open while a URL is being searched, even though it's empty
+	urlQueue := &URLQueue{}
+	urlQueue.Push("https://example.com/")
+	url, depth, ok := urlQueue.Next(context.Background())
+	assert.True(t, ok, "Expected a URL")
+	assert.Equal(t, 0, depth, "Expected input URLs at depth 0")
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		//Found while searching the page, before it's marked as done
+		urlQueue.PushAt(url+"app.js", depth+1)
+		urlQueue.Done()
+	}()
+	url, depth, ok = urlQueue.Next(context.Background())
+	assert.True(t, ok, "Expected the URL found while searching")
+	assert.Equal(t, "https://example.com/app.js", url, "Unexpected URL")
+	assert.Equal(t, 1, depth, "Expected the URL one depth past the page it was found on")
+
+	//Test case: Once nothing is queued or being searched, the queue is closed
+	urlQueue.Done()
+	_, _, ok = urlQueue.Next(context.Background())
+	assert.False(t, ok, "Expected the queue to be closed")
+	assert.False(t, urlQueue.Push("https://example.com/late.js"), "Expected pushing to a finished queue to be ignored")
+}
+
+func TestNormalizeURL(t *testing.T) {
+	//Test case: The host is lowercased, but not the path
+	assert.Equal(t, "https://example.com/App.js", normalizeURL("https://EXAMPLE.com/App.js"), "Expected a lowercase host")
+
+	//Test case: Default ports are removed, other ports are kept
+	assert.Equal(t, "https://example.com/app.js", normalizeURL("https://example.com:443/app.js"), "Expected the https port to be removed")
+	assert.Equal(t, "http://example.com/app.js", normalizeURL("http://example.com:80/app.js"), "Expected the http port to be removed")
+	assert.Equal(t, "http://example.com:443/app.js", normalizeURL("http://example.com:443/app.js"), "Expected a non-default port to be kept")
+	assert.Equal(t, "https://[::1]/app.js", normalizeURL("https://[::1]:443/app.js"), "Expected the brackets to be kept for IPv6")
+
+	//Test case: Fragments are removed, queries are kept
+	assert.Equal(t, "https://example.com/app.js?v=2", normalizeURL("https://example.com/app.js?v=2#main"), "Expected the fragment to be removed")
+
+	//Test case: An empty path is a trailing slash, other trailing slashes are kept
+	assert.Equal(t, "https://example.com/", normalizeURL("https://example.com"), "Expected an empty path to become /")
+	assert.Equal(t, "https://example.com/?q=1", normalizeURL("https://example.com?q=1"), "Expected an empty path to become / before the query")
+	assert.Equal(t, "https://example.com/docs/", normalizeURL("https://example.com/docs/"), "Expected the trailing slash to be kept")
+
+	//Test case: URLs without a host are left alone
+	assert.Equal(t, "/app.js", normalizeURL("/app.js"), "Expected a path to be unchanged")
+}
+
+func TestURLQueueNormalized(t *testing.T) {
+	//Test case: The same URL written differently is only queued once, in its normalized form
+	urlQueue := &URLQueue{}
+	assert.True(t, urlQueue.Push("https://Example.com:443"), "Expected the first URL to be queued")
+	assert.False(t, urlQueue.Push("https://example.com/"), "Expected the trailing slash to be a duplicate")
+	assert.False(t, urlQueue.Push("https://example.com/#top"), "Expected the fragment to be a duplicate")
+	url, ok := urlQueue.Pop()
+	assert.True(t, ok, "Expected a URL")
+	assert.Equal(t, "https://example.com/", url, "Expected the normalized URL")
+}
@@ -0,0 +1,226 @@
+package scanner
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+// A reportGroup is the findings of one type in the html report
+type reportGroup struct {
+	Type string
+	//The highest severity of the findings in the group, which the groups are sorted by
+	Severity string
+	Findings []Finding
+	URLs     int
+}
+
+// A severityCount is how many findings of a severity there are, for the totals at the top of the html report
+type severityCount struct {
+	Severity string
+	Count    int
+}
+
+// reportData is what the html report is built from
+type reportData struct {
+	Generated  string
+	Total      int
+	URLs       int
+	Severities []severityCount
+	Groups     []reportGroup
+}
+
+// The html report, which is self-contained so it can be sent to anyone and opened without webstrings or a server. The
+// script only sorts and filters the rows that are already there.
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"rank":     reportRank,
+	"location": reportLocation,
+	"plural":   plural,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>webstrings report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { margin-bottom: 0.2em; }
+.meta { color: #656d76; margin-bottom: 1.5em; }
+.totals span { display: inline-block; margin: 0 0.5em 0.5em 0; padding: 0.3em 0.8em; border-radius: 1em; }
+.controls { margin: 1em 0; }
+.controls input { width: 24em; padding: 0.3em; }
+details.group { margin-bottom: 1em; border: 1px solid #d0d7de; border-radius: 6px; }
+details.group > summary { padding: 0.6em 1em; cursor: pointer; font-weight: 600; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.4em 1em; border-top: 1px solid #d0d7de; vertical-align: top; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; }
+td.value { font-family: ui-monospace, Menlo, Consolas, monospace; word-break: break-all; }
+td.url { word-break: break-all; }
+pre { white-space: pre-wrap; word-break: break-all; margin: 0.4em 0 0; }
+.severity { font-weight: 600; text-transform: capitalize; }
+.sev-critical { background: #ffd8d3; color: #82071e; }
+.sev-high { background: #ffe2cc; color: #953800; }
+.sev-medium { background: #fff1c2; color: #7d4e00; }
+.sev-low { background: #ddf4ff; color: #0a3069; }
+.sev-info, .sev- { background: #eaeef2; color: #424a53; }
+</style>
+</head>
+<body>
+<h1>webstrings report</h1>
+<div class="meta">Generated {{.Generated}}, {{.Total}} {{plural .Total "finding" "findings"}} in {{.URLs}} {{plural .URLs "URL" "URLs"}}</div>
+<div class="totals">
+{{- range .Severities}}
+<span class="severity sev-{{.Severity}}">{{.Severity}}: {{.Count}}</span>
+{{- end}}
+</div>
+<div class="controls">
+<input id="filter" type="search" placeholder="Filter by type, value, URL or source">
+<select id="severity">
+<option value="-1">Every severity</option>
+<option value="1">Low and above</option>
+<option value="2">Medium and above</option>
+<option value="3">High and above</option>
+<option value="4">Critical only</option>
+</select>
+</div>
+{{- range .Groups}}
+<details class="group" open>
+<summary><span class="severity sev-{{.Severity}}">{{.Severity}}</span> {{.Type}} ({{len .Findings}} in {{.URLs}} {{plural .URLs "URL" "URLs"}})</summary>
+<table>
+<thead><tr><th>Severity</th><th>Value</th><th>URL</th><th>Source</th><th>Count</th></tr></thead>
+<tbody>
+{{- range .Findings}}
+<tr data-rank="{{rank .Severity}}">
+<td class="severity sev-{{.Severity}}" data-sort="{{rank .Severity}}">{{.Severity}}</td>
+<td class="value">{{.Value}}
+{{- if .Key}}<br>Key: {{.Key}}{{end}}
+{{- if .Details}}<br>{{.Details}}{{end}}
+{{- if .Verified}}<br>{{.Verified}}{{end}}
+{{- if .Context}}<details><summary>Context</summary><pre>{{.Context}}</pre></details>{{end}}</td>
+<td class="url"><a href="{{.URL}}">{{location .}}</a>
+{{- range .OtherURLs}}<br><a href="{{.}}">{{.}}</a>{{end}}</td>
+<td>{{.Source}}</td>
+<td data-sort="{{.Count}}">{{.Count}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</details>
+{{- end}}
+<script>
+const filter = document.getElementById("filter");
+const severity = document.getElementById("severity");
+function update() {
+	const text = filter.value.toLowerCase();
+	const minRank = Number(severity.value);
+	for (const group of document.querySelectorAll("details.group")) {
+		let visible = 0;
+		for (const row of group.querySelectorAll("tbody tr")) {
+			const show = Number(row.dataset.rank) >= minRank && (group.querySelector("summary").textContent + row.textContent).toLowerCase().includes(text);
+			row.hidden = !show;
+			if (show) visible++;
+		}
+		group.hidden = visible === 0;
+	}
+}
+filter.addEventListener("input", update);
+severity.addEventListener("change", update);
+for (const header of document.querySelectorAll("th")) {
+	header.addEventListener("click", () => {
+		const tbody = header.closest("table").querySelector("tbody");
+		const column = header.cellIndex;
+		const descending = header.dataset.order !== "desc";
+		header.dataset.order = descending ? "desc" : "asc";
+		const key = row => {
+			const cell = row.cells[column];
+			return cell.dataset.sort !== undefined ? Number(cell.dataset.sort) : cell.textContent.toLowerCase();
+		};
+		const rows = [...tbody.rows].sort((a, b) => {
+			const x = key(a), y = key(b);
+			return (x < y ? -1 : x > y ? 1 : 0) * (descending ? -1 : 1);
+		});
+		tbody.append(...rows);
+	});
+}
+</script>
+</body>
+</html>
+`))
+
+// reportRank gets the index of a severity in severityLevels for sorting and filtering the html report, or -1 if the
+// finding doesn't have one
+func reportRank(severity string) int {
+	rank, ok := severityRank(severity)
+	if !ok {
+		return -1
+	}
+	return rank
+}
+
+// reportLocation gets the URL of a finding with its line and column, like the location flag in the text output
+func reportLocation(finding Finding) string {
+	if finding.Line > 0 && inURL(finding) {
+		return fmt.Sprintf("%s:%d:%d", finding.URL, finding.Line, finding.Column)
+	}
+	return finding.URL
+}
+
+// writeHTMLReport writes the findings as a self-contained html report for the html format
+//
+// Findings are grouped by type, with the most severe groups first, and sorted by URL in each group. The report has the
+// number of findings of each severity at the top, and can be filtered and sorted in the browser.
+//
+// Parameters:
+//   - w: The writer to write the report to.
+//   - findings: Every finding from the run.
+//
+// Returns:
+//   - error
+func writeHTMLReport(w io.Writer, findings []Finding) error {
+	data := reportData{Generated: time.Now().Format(time.RFC1123), Total: len(findings)}
+
+	groups := map[string]*reportGroup{}
+	urls := map[string]struct{}{}
+	groupURLs := map[string]map[string]struct{}{}
+	counts := map[string]int{}
+	for _, finding := range findings {
+		group, ok := groups[finding.Type]
+		if !ok {
+			group = &reportGroup{Type: finding.Type, Severity: finding.Severity}
+			groups[finding.Type] = group
+			groupURLs[finding.Type] = map[string]struct{}{}
+		}
+		if reportRank(finding.Severity) > reportRank(group.Severity) {
+			group.Severity = finding.Severity
+		}
+		group.Findings = append(group.Findings, finding)
+		groupURLs[finding.Type][finding.URL] = struct{}{}
+		urls[finding.URL] = struct{}{}
+		counts[finding.Severity]++
+	}
+	data.URLs = len(urls)
+
+	for i := len(severityLevels) - 1; i >= 0; i-- {
+		if count := counts[severityLevels[i]]; count > 0 {
+			data.Severities = append(data.Severities, severityCount{Severity: severityLevels[i], Count: count})
+		}
+	}
+	for findingType, group := range groups {
+		group.URLs = len(groupURLs[findingType])
+		sort.SliceStable(group.Findings, func(i, j int) bool {
+			return group.Findings[i].URL < group.Findings[j].URL
+		})
+		data.Groups = append(data.Groups, *group)
+	}
+	sort.Slice(data.Groups, func(i, j int) bool {
+		x, y := reportRank(data.Groups[i].Severity), reportRank(data.Groups[j].Severity)
+		if x != y {
+			return x > y
+		}
+		return data.Groups[i].Type < data.Groups[j].Type
+	})
+
+	return reportTemplate.Execute(w, data)
+}
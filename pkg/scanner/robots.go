@@ -0,0 +1,204 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	netUrl "net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// A robotsEntry holds the rules for a host, the mutex makes sure concurrent searches of the same host only fetch it once
+type robotsEntry struct {
+	mu sync.Mutex
+	//Nil until a fetch of the robots.txt file completes
+	rules *robotsRules
+}
+
+// A robotsRule is a single Allow or Disallow line from a robots.txt file
+type robotsRule struct {
+	allow   bool
+	pattern *regexp.Regexp
+	//The length of the path in the rule, the longest matching rule is the one that applies
+	length int
+}
+
+// The robotsRules are the rules from a robots.txt file that apply to webstrings
+type robotsRules struct {
+	rules []robotsRule
+}
+
+// parseRobots parses the contents of a robots.txt file
+//
+// The rules in groups for the webstrings user-agent are used if there are any, otherwise the rules for * are used.
+//
+// Parameters:
+//   - text: The contents of the robots.txt file.
+//
+// Returns:
+//   - *robotsRules: The rules that apply to webstrings.
+func parseRobots(text string) *robotsRules {
+	named := &robotsRules{}
+	wildcard := &robotsRules{}
+
+	//The groups that the current block of user-agent lines apply to
+	var agents []string
+	//Whether the last line was a user-agent line, so consecutive user-agent lines are part of the same group
+	inAgents := false
+	for _, line := range strings.Split(text, "\n") {
+		//Remove comments and whitespace, including the \r from files with CRLF line endings
+		line, _, _ = strings.Cut(line, "#")
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				agents = nil
+			}
+			agents = append(agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			//An empty Disallow means everything is allowed, so it doesn't need a rule
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", pattern: robotsPattern(value), length: len(value)}
+			for _, agent := range agents {
+				if agent == "*" {
+					wildcard.rules = append(wildcard.rules, rule)
+				} else if strings.Contains(agent, "webstrings") {
+					named.rules = append(named.rules, rule)
+				}
+			}
+		default:
+			//Other lines like Sitemap or Crawl-delay end the user-agent lines, but not the group
+			inAgents = false
+		}
+	}
+
+	if len(named.rules) > 0 {
+		return named
+	}
+	return wildcard
+}
+
+// robotsPattern converts a robots.txt path into a regex, where * matches anything and a trailing $ matches the end of the path
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(path), `\*`, ".*")
+	if anchored {
+		pattern += "$"
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// allowed checks if a path can be searched under these rules
+//
+// The longest matching rule applies, and Allow wins if an Allow and Disallow rule are the same length.
+//
+// Parameters:
+//   - path: The path of the URL, including the query string.
+//
+// Returns:
+//   - bool: True if the path can be searched.
+func (r *robotsRules) allowed(path string) bool {
+	allow := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > longest || (rule.length == longest && rule.allow) {
+			allow = rule.allow
+			longest = rule.length
+		}
+	}
+	return allow
+}
+
+// fetchRobots gets and parses the robots.txt file for a host
+//
+// If the site doesn't have a robots.txt file, or responds with anything other than a 200, everything is allowed. Only the
+// first maxResponseSize bytes of the file are read.
+//
+// Parameters:
+//   - ctx: The context for the search, used to cancel the request if needed.
+//   - origin: The scheme and host to get the robots.txt file from, e.g. https://example.com
+//
+// Returns:
+//   - *robotsRules: The rules that apply to webstrings.
+//   - bool: False if the request failed or was cancelled, so the rules shouldn't be cached.
+func (s *scanner) fetchRobots(ctx context.Context, origin string) (*robotsRules, bool) {
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{}, false
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return &robotsRules{}, false
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return &robotsRules{}, true
+	}
+
+	bytes, err := io.ReadAll(io.LimitReader(res.Body, s.maxResponseSize))
+	if err != nil {
+		return &robotsRules{}, false
+	}
+	return parseRobots(string(bytes)), true
+}
+
+// robotsAllowed checks the robots.txt file of the URL's host to see if the URL can be searched
+//
+// Parameters:
+//   - ctx: The context for the search, used to cancel the request if needed.
+//   - url: The URL to check.
+//
+// Returns:
+//   - bool: True if the URL can be searched.
+func (s *scanner) robotsAllowed(ctx context.Context, url string) bool {
+	//URLs without a scheme are requested over https, see addScheme
+	parsedUrl, err := netUrl.Parse(addScheme(url))
+	//Leave URLs that can't be checked for getContents to report
+	if err != nil || parsedUrl.Host == "" {
+		return true
+	}
+
+	origin := parsedUrl.Scheme + "://" + parsedUrl.Host
+	value, _ := s.robotsCache.LoadOrStore(origin, &robotsEntry{})
+	entry := value.(*robotsEntry)
+	entry.mu.Lock()
+	rules := entry.rules
+	if rules == nil {
+		//A failed fetch allows this URL, but isn't cached so the next URL on the host tries again
+		var completed bool
+		rules, completed = s.fetchRobots(ctx, origin)
+		if completed {
+			entry.rules = rules
+		}
+	}
+	entry.mu.Unlock()
+
+	path := parsedUrl.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if parsedUrl.RawQuery != "" {
+		path += "?" + parsedUrl.RawQuery
+	}
+	return rules.allowed(path)
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRobots(t *testing.T) {
+	//Test case: Wildcard group
+	rules := parseRobots("User-agent: *\r\nDisallow: /private\r\nAllow: /private/public # comment\r\n")
+	assert.True(t, rules.allowed("/"), "Expected / to be allowed")
+	assert.False(t, rules.allowed("/private/app.js"), "Expected /private to be disallowed")
+	assert.True(t, rules.allowed("/private/public/app.js"), "Expected the longer Allow rule to apply")
+
+	//Test case: Wildcards and end of path anchors
+	rules = parseRobots("User-agent: *\nDisallow: /*.js$\nDisallow: /search?\n")
+	assert.False(t, rules.allowed("/static/app.js"), "Expected .js files to be disallowed")
+	assert.True(t, rules.allowed("/static/app.js.map"), "Expected $ to anchor the end of the path")
+	assert.False(t, rules.allowed("/search?q=1"), "Expected the query string to be checked")
+
+	//Test case: Groups for webstrings are used over the wildcard group
+	rules = parseRobots("User-agent: googlebot\nUser-agent: webstrings\nDisallow: /admin\n\nUser-agent: *\nDisallow: /\n")
+	assert.True(t, rules.allowed("/app.js"), "Expected the wildcard group to be ignored")
+	assert.False(t, rules.allowed("/admin"), "Expected the webstrings group to apply")
+
+	//Test case: Groups for other user-agents are ignored, and an empty Disallow allows everything
+	rules = parseRobots("User-agent: googlebot\nDisallow: /\n\nUser-agent: *\nDisallow:\n")
+	assert.True(t, rules.allowed("/app.js"), "Expected everything to be allowed")
+}
+
+func TestRobotsAllowed(t *testing.T) {
+	s := newScanner()
+	var requests int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&requests, 1)
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+			return
+		}
+		fmt.Fprint(w, "var message = \"Hello, World!\";")
+	}))
+	defer mockServer.Close()
+	ctx := context.TODO()
+
+	//Test case: Allowed and disallowed paths, with robots.txt only fetched once
+	assert.True(t, s.robotsAllowed(ctx, mockServer.URL+"/app.js"), "Expected /app.js to be allowed")
+	assert.False(t, s.robotsAllowed(ctx, mockServer.URL+"/private/app.js"), "Expected /private to be disallowed")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected robots.txt to be cached")
+
+	//Test case: Disallowed URLs aren't searched unless the ignore-robots flag is used
+	opts := Options{}
+	out, err := s.search(ctx, mockServer.URL+"/private/app.js", 0, opts, &URLQueue{})
+	assert.Nil(t, err, "Unexpected error")
+	assert.Nil(t, out, "Expected a disallowed URL to be skipped")
+
+	opts.IgnoreRobots = true
+	out, err = s.search(ctx, mockServer.URL+"/private/app.js", 0, opts, &URLQueue{})
+	assert.Nil(t, err, "Unexpected error")
+	if assert.Len(t, out, 1, "Expected the ignore-robots flag to search the URL") {
+		assert.Equal(t, "Hello, World!", out[0].Value, "Unexpected finding")
+	}
+
+	//Test case: Missing robots.txt allows everything
+	missingServer := httptest.NewServer(http.NotFoundHandler())
+	defer missingServer.Close()
+	assert.True(t, s.robotsAllowed(ctx, missingServer.URL+"/private"), "Expected everything to be allowed without a robots.txt")
+
+	//Test case: A cancelled fetch allows the URL but isn't cached
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	s.robotsCache = &sync.Map{}
+	atomic.StoreInt32(&requests, 0)
+	assert.True(t, s.robotsAllowed(cancelled, mockServer.URL+"/private/app.js"), "Expected a failed fetch to allow the URL")
+	assert.False(t, s.robotsAllowed(ctx, mockServer.URL+"/private/app.js"), "Expected robots.txt to be fetched again")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected only the completed fetch to reach the server")
+
+	//Test case: Only the first maxResponseSize bytes are read
+	s.maxResponseSize = int64(len("User-agent: *\nDisall"))
+	s.robotsCache = &sync.Map{}
+	assert.True(t, s.robotsAllowed(ctx, mockServer.URL+"/private/app.js"), "Expected the rule to be cut off")
+}
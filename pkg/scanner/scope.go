@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	netUrl "net/url"
+	"strings"
+)
+
+// inScope checks if the host of a URL matches one of the crawlScope patterns
+//
+// Parameters:
+//   - url: The URL to check.
+//
+// Returns:
+//   - bool: True if the URL is in scope, or if there is no scope.
+func (s *scanner) inScope(url string) bool {
+	if len(s.crawlScope) == 0 {
+		return true
+	}
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsedUrl.Hostname())
+	for _, pattern := range s.crawlScope {
+		pattern = scopeHost(pattern)
+		if strings.HasPrefix(pattern, "*.") {
+			//*.example.com matches sub.example.com and a.b.example.com, but not example.com itself
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeHost gets the host from a scope pattern, so patterns with a port like example.com:8443 or an IPv6 address in
+// brackets like [::1] match the same hosts as the URLs being searched, which are compared without the port or brackets
+func scopeHost(pattern string) string {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	parsedUrl, err := netUrl.Parse("//" + pattern)
+	if err != nil || parsedUrl.Hostname() == "" {
+		return pattern
+	}
+	return parsedUrl.Hostname()
+}
+
+// seedScope gets the default scope from the hosts of the input URLs, so only scripts on the sites being searched are followed
+//
+// Parameters:
+//   - urls: The input URLs.
+//
+// Returns:
+//   - []string: The hosts of the URLs, without duplicates.
+func seedScope(urls []string) []string {
+	var scope []string
+	seen := map[string]struct{}{}
+	for _, url := range urls {
+		//URLs from a file can leave out the scheme, which getContents adds https:// for
+		parsedUrl, err := netUrl.Parse(addScheme(url))
+		if err != nil || parsedUrl.Hostname() == "" {
+			continue
+		}
+		host := strings.ToLower(parsedUrl.Hostname())
+		if _, ok := seen[host]; ok {
+			continue
+		}
+		seen[host] = struct{}{}
+		scope = append(scope, host)
+	}
+	return scope
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInScope(t *testing.T) {
+	s := newScanner()
+
+	//Test case: No scope allows everything
+	s.crawlScope = nil
+	assert.True(t, s.inScope("https://cdn.example.net/lib.js"), "Expected everything to be in scope")
+
+	//Test case: Exact hosts and wildcards, ignoring case and ports
+	s.crawlScope = []string{"example.com", "*.Example.org"}
+	assert.True(t, s.inScope("https://EXAMPLE.com:8443/app.js"), "Expected the exact host to be in scope")
+	assert.False(t, s.inScope("https://www.example.com/app.js"), "Expected subdomains to need a wildcard")
+	assert.True(t, s.inScope("https://static.example.org/app.js"), "Expected the wildcard to match a subdomain")
+	assert.True(t, s.inScope("https://a.b.example.org/app.js"), "Expected the wildcard to match nested subdomains")
+	assert.False(t, s.inScope("https://example.org/app.js"), "Expected the wildcard not to match the domain itself")
+	assert.False(t, s.inScope("https://notexample.org/app.js"), "Expected the wildcard to only match whole labels")
+	assert.False(t, s.inScope("https://cdn.example.net/lib.js"), "Expected other domains to be out of scope")
+
+	//Test case: Patterns with ports and IPv6 hosts in brackets
+	s.crawlScope = []string{"example.net:8443", "[::1]", "[2001:DB8::1]:8080"}
+	assert.True(t, s.inScope("https://example.net/app.js"), "Expected the port in the pattern to be ignored")
+	assert.True(t, s.inScope("http://[::1]:3000/app.js"), "Expected the IPv6 host to be in scope")
+	assert.True(t, s.inScope("http://[2001:db8::1]/app.js"), "Expected the IPv6 host with a port to be in scope")
+	assert.False(t, s.inScope("http://[::2]/app.js"), "Expected other IPv6 hosts to be out of scope")
+}
+
+func TestSeedScope(t *testing.T) {
+	//Test case: Hosts of the input URLs, with duplicates and missing schemes
+	scope := seedScope([]string{"https://example.com/", "http://Example.com:8080/app.js", "www.example.org/page", "::invalid"})
+	assert.Equal(t, []string{"example.com", "www.example.org"}, scope, "Unexpected scope")
+
+	//Test case: IPv6 hosts and hosts with ports but no scheme
+	scope = seedScope([]string{"http://[::1]:8080/", "[2001:db8::1]/app.js", "example.net:8443/page"})
+	assert.Equal(t, []string{"::1", "2001:db8::1", "example.net"}, scope, "Unexpected scope")
+}
+
+func TestSearchWithoutScheme(t *testing.T) {
+	s := newScanner()
+	var requested []string
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		default:
+			fmt.Fprint(w, `<script src="/app.js"></script><script src="js/lib.js"></script>`)
+		}
+	}))
+	defer tlsServer.Close()
+	client, err := newHTTPClient(time.Second, "", true)
+	assert.Nil(t, err, "Unexpected error")
+	s.httpClient = client
+	s.output = io.Discard
+	//The host and port of the server, like 127.0.0.1:8443
+	host := strings.TrimPrefix(tlsServer.URL, "https://")
+	s.crawlScope = seedScope([]string{host})
+
+	//Test case: Scripts are resolved against the host and port of a URL without a scheme
+	urlQueue := &URLQueue{}
+	_, err = s.search(context.TODO(), host+"/page/", 0, Options{}, urlQueue)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{tlsServer.URL + "/app.js", tlsServer.URL + "/page/js/lib.js"}, urlQueue.queue, "Unexpected queued scripts")
+
+	//Test case: robots.txt is checked for URLs without a scheme - This will print a Warning, but pass
+	requested = nil
+	_, err = s.search(context.TODO(), host+"/private/page", 0, Options{}, &URLQueue{})
+	assert.Nil(t, err, "Unexpected error")
+	assert.Empty(t, requested, "Expected the disallowed page not to be requested")
+}
+
+func TestSearchScope(t *testing.T) {
+	s := newScanner()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<script src="/app.js"></script><script src="https://cdn.example.net/lib.js"></script>`)
+	}))
+	defer mockServer.Close()
+	s.output = io.Discard
+
+	//Test case: Only scripts on the input URL's host are queued
+	s.crawlScope = seedScope([]string{mockServer.URL})
+	urlQueue := &URLQueue{}
+	opts := Options{IgnoreRobots: true}
+	_, err := s.search(context.TODO(), mockServer.URL, 0, opts, urlQueue)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{mockServer.URL + "/app.js"}, urlQueue.queue, "Expected the off-scope script to be skipped")
+}
@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"net/http"
+	netUrl "net/url"
+	"strconv"
+	"strings"
+)
+
+// Finding type for missing or weak security headers on a page, reported with the headers flag
+const securityHeaderFinding = "Security Header"
+
+// HSTS max-age below this many seconds (180 days) is reported as weak, since it's the minimum for most scanners and
+// preload lists want a year
+const minHSTSMaxAge = 180 * 24 * 60 * 60
+
+// checkSecurityHeaders reports the missing and weak Content-Security-Policy, Strict-Transport-Security and
+// X-Frame-Options headers of a page
+//
+// Each finding has the header and its value, or "not set" if it's missing, with the issue in the Details.
+//
+// Parameters:
+//   - url: The URL of the page, HSTS is only checked for https.
+//   - header: The response headers of the page.
+//
+// Returns:
+//   - []Finding: A finding for each issue, empty if the headers look fine.
+func checkSecurityHeaders(url string, header http.Header) []Finding {
+	var findings []Finding
+	report := func(name string, details string) {
+		value := name + " not set"
+		if header.Get(name) != "" {
+			value = name + ": " + strings.Join(header.Values(name), ", ")
+		}
+		findings = append(findings, Finding{URL: url, Type: securityHeaderFinding, Value: value, Source: "headers", Details: details})
+	}
+
+	policies := parseCSP(header.Values("Content-Security-Policy"))
+	if len(policies) == 0 {
+		if header.Get("Content-Security-Policy-Report-Only") != "" {
+			report("Content-Security-Policy-Report-Only", "only reports violations, nothing is blocked")
+		} else {
+			report("Content-Security-Policy", "missing")
+		}
+	} else {
+		//Browsers enforce every policy, so a script only runs if all of them allow it, and an issue is only reported if
+		//none of the policies that restrict scripts block it. script-src falls back to default-src, and a policy with
+		//neither doesn't restrict scripts at all.
+		var scriptPolicies [][]string
+		for _, csp := range policies {
+			scriptSources, ok := csp["script-src"]
+			if !ok {
+				scriptSources, ok = csp["default-src"]
+			}
+			if ok {
+				scriptPolicies = append(scriptPolicies, scriptSources)
+			}
+		}
+		switch {
+		case len(scriptPolicies) == 0:
+			report("Content-Security-Policy", "no script-src or default-src, so scripts aren't restricted")
+		case everyPolicy(scriptPolicies, allowsInline):
+			report("Content-Security-Policy", "allows inline scripts with 'unsafe-inline'")
+		case everyPolicy(scriptPolicies, allowsAnyHost):
+			report("Content-Security-Policy", "allows scripts from any host")
+		case everyPolicy(scriptPolicies, func(sources []string) bool { return containsSource(sources, "'unsafe-eval'") }):
+			report("Content-Security-Policy", "allows eval with 'unsafe-eval'")
+		}
+	}
+
+	if parsedUrl, err := netUrl.Parse(url); err == nil && parsedUrl.Scheme == "https" {
+		hsts := header.Get("Strict-Transport-Security")
+		if hsts == "" {
+			report("Strict-Transport-Security", "missing")
+		} else if maxAge, ok := hstsMaxAge(hsts); !ok || maxAge < minHSTSMaxAge {
+			report("Strict-Transport-Security", "max-age is less than 180 days")
+		}
+	}
+
+	//frame-ancestors replaces X-Frame-Options in browsers that support it
+	if !hasDirective(policies, "frame-ancestors") {
+		frameOptions := strings.ToUpper(strings.TrimSpace(header.Get("X-Frame-Options")))
+		if frameOptions == "" {
+			report("X-Frame-Options", "missing, and there's no frame-ancestors in the Content-Security-Policy")
+		} else if frameOptions != "DENY" && frameOptions != "SAMEORIGIN" {
+			report("X-Frame-Options", "should be DENY or SAMEORIGIN, browsers ignore anything else")
+		}
+	}
+	return findings
+}
+
+// parseCSP parses Content-Security-Policy headers into the directives of each policy, with the directive names
+// lowercased
+//
+// Each header can have more than one policy separated by commas. When a policy sets a directive more than once, only the
+// first one is used, like browsers do.
+func parseCSP(headers []string) []map[string][]string {
+	var policies []map[string][]string
+	for _, header := range headers {
+		for _, policy := range strings.Split(header, ",") {
+			directives := map[string][]string{}
+			for _, directive := range strings.Split(policy, ";") {
+				fields := strings.Fields(directive)
+				if len(fields) == 0 {
+					continue
+				}
+				name := strings.ToLower(fields[0])
+				if _, ok := directives[name]; !ok {
+					directives[name] = fields[1:]
+				}
+			}
+			policies = append(policies, directives)
+		}
+	}
+	return policies
+}
+
+// hasDirective checks if any of the policies from parseCSP sets a directive
+func hasDirective(policies []map[string][]string, name string) bool {
+	for _, policy := range policies {
+		if _, ok := policy[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// everyPolicy checks if the script sources of every policy that restricts scripts allow something
+func everyPolicy(scriptPolicies [][]string, allows func(sources []string) bool) bool {
+	for _, sources := range scriptPolicies {
+		if !allows(sources) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsInline checks if a CSP source list allows inline scripts. Browsers ignore 'unsafe-inline' when there's a nonce,
+// a hash or 'strict-dynamic'.
+func allowsInline(sources []string) bool {
+	return containsSource(sources, "'unsafe-inline'") && !hasNonceOrHash(sources) && !containsSource(sources, "'strict-dynamic'")
+}
+
+// allowsAnyHost checks if a CSP source list allows scripts from any host. Browsers ignore host and scheme sources when
+// there's 'strict-dynamic', so only scripts with a nonce or hash, and the scripts they load, can run.
+func allowsAnyHost(sources []string) bool {
+	if containsSource(sources, "'strict-dynamic'") {
+		return false
+	}
+	return containsSource(sources, "*") || containsSource(sources, "data:") || containsSource(sources, "https:") || containsSource(sources, "http:")
+}
+
+// containsSource checks if a CSP source list has a source, ignoring case
+func containsSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if strings.EqualFold(s, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNonceOrHash checks if a CSP source list has a nonce or hash, which makes browsers ignore 'unsafe-inline'
+func hasNonceOrHash(sources []string) bool {
+	for _, source := range sources {
+		source = strings.ToLower(source)
+		if strings.HasPrefix(source, "'nonce-") || strings.HasPrefix(source, "'sha256-") || strings.HasPrefix(source, "'sha384-") || strings.HasPrefix(source, "'sha512-") {
+			return true
+		}
+	}
+	return false
+}
+
+// hstsMaxAge gets the max-age from a Strict-Transport-Security header, the bool is false if it doesn't have a valid one
+func hstsMaxAge(hsts string) (int, bool) {
+	for _, directive := range strings.Split(hsts, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(strings.TrimSpace(name), "max-age") {
+			maxAge, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+			return maxAge, err == nil
+		}
+	}
+	return 0, false
+}
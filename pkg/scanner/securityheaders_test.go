@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSecurityHeaders(t *testing.T) {
+	good := http.Header{
+		"Content-Security-Policy":   {"default-src 'self'; script-src 'self' 'nonce-abc' 'unsafe-inline'"},
+		"Strict-Transport-Security": {"max-age=31536000; includeSubDomains"},
+		"X-Frame-Options":           {"DENY"},
+	}
+
+	//Test case: Nothing is reported for strong headers
+	assert.Empty(t, checkSecurityHeaders("https://example.com/", good), "Expected no findings")
+
+	//Test case: Missing headers
+	findings := checkSecurityHeaders("https://example.com/", http.Header{})
+	assert.Equal(t, []Finding{
+		{URL: "https://example.com/", Type: securityHeaderFinding, Value: "Content-Security-Policy not set", Source: "headers", Details: "missing"},
+		{URL: "https://example.com/", Type: securityHeaderFinding, Value: "Strict-Transport-Security not set", Source: "headers", Details: "missing"},
+		{URL: "https://example.com/", Type: securityHeaderFinding, Value: "X-Frame-Options not set", Source: "headers", Details: "missing, and there's no frame-ancestors in the Content-Security-Policy"},
+	}, findings, "Expected each missing header")
+
+	//Test case: HSTS is only checked over https, and frame-ancestors replaces X-Frame-Options
+	findings = checkSecurityHeaders("http://example.com/", http.Header{"Content-Security-Policy": {"default-src 'self'; frame-ancestors 'none'"}})
+	assert.Empty(t, findings, "Expected no findings")
+
+	//Test case: Weak headers are reported with their values
+	weak := []struct {
+		header  http.Header
+		value   string
+		details string
+	}{
+		{http.Header{"Content-Security-Policy": {"script-src 'self' 'unsafe-inline'"}}, "Content-Security-Policy: script-src 'self' 'unsafe-inline'", "allows inline scripts with 'unsafe-inline'"},
+		{http.Header{"Content-Security-Policy": {"default-src https:"}}, "Content-Security-Policy: default-src https:", "allows scripts from any host"},
+		{http.Header{"Content-Security-Policy": {"default-src 'self' 'unsafe-eval'"}}, "Content-Security-Policy: default-src 'self' 'unsafe-eval'", "allows eval with 'unsafe-eval'"},
+		{http.Header{"Content-Security-Policy": {"img-src 'self'"}}, "Content-Security-Policy: img-src 'self'", "no script-src or default-src, so scripts aren't restricted"},
+		{http.Header{"Content-Security-Policy-Report-Only": {"default-src 'self'"}}, "Content-Security-Policy-Report-Only: default-src 'self'", "only reports violations, nothing is blocked"},
+		{http.Header{"Strict-Transport-Security": {"max-age=3600"}}, "Strict-Transport-Security: max-age=3600", "max-age is less than 180 days"},
+		{http.Header{"X-Frame-Options": {"ALLOW-FROM https://example.org"}}, "X-Frame-Options: ALLOW-FROM https://example.org", "should be DENY or SAMEORIGIN, browsers ignore anything else"},
+	}
+	for _, test := range weak {
+		header := good.Clone()
+		for name, values := range test.header {
+			header[name] = values
+		}
+		if _, ok := test.header["Content-Security-Policy-Report-Only"]; ok {
+			header.Del("Content-Security-Policy")
+		}
+		findings = checkSecurityHeaders("https://example.com/", header)
+		assert.Equal(t, []Finding{{URL: "https://example.com/", Type: securityHeaderFinding, Value: test.value, Source: "headers", Details: test.details}}, findings, "Unexpected findings for %s", test.value)
+	}
+}
+
+func TestCheckSecurityHeadersPolicies(t *testing.T) {
+	header := http.Header{
+		"Strict-Transport-Security": {"max-age=31536000"},
+		"X-Frame-Options":           {"DENY"},
+	}
+
+	//Test case: Every policy is enforced, so a weak policy alongside a strict one isn't reported
+	header["Content-Security-Policy"] = []string{"script-src 'self' 'unsafe-inline'", "script-src 'self'"}
+	assert.Empty(t, checkSecurityHeaders("https://example.com/", header), "Expected the strict policy to block inline scripts")
+	header["Content-Security-Policy"] = []string{"img-src 'self', default-src https:; script-src 'self'"}
+	assert.Empty(t, checkSecurityHeaders("https://example.com/", header), "Expected the policies separated by a comma to be checked separately")
+
+	//Test case: An issue is reported when every policy has it
+	header["Content-Security-Policy"] = []string{"script-src 'self' 'unsafe-inline'", "default-src * 'unsafe-inline'"}
+	findings := checkSecurityHeaders("https://example.com/", header)
+	if assert.Len(t, findings, 1, "Expected the inline scripts to be reported") {
+		assert.Equal(t, "Content-Security-Policy: script-src 'self' 'unsafe-inline', default-src * 'unsafe-inline'", findings[0].Value, "Expected every policy in the value")
+		assert.Equal(t, "allows inline scripts with 'unsafe-inline'", findings[0].Details, "Unexpected details")
+	}
+
+	//Test case: 'strict-dynamic' makes browsers ignore 'unsafe-inline' and host sources
+	header["Content-Security-Policy"] = []string{"script-src 'strict-dynamic' 'unsafe-inline' https:"}
+	assert.Empty(t, checkSecurityHeaders("https://example.com/", header), "Expected 'strict-dynamic' to be respected")
+
+	//Test case: frame-ancestors in any policy replaces X-Frame-Options
+	header.Del("X-Frame-Options")
+	header["Content-Security-Policy"] = []string{"default-src 'self'", "frame-ancestors 'none'"}
+	assert.Empty(t, checkSecurityHeaders("https://example.com/", header), "Expected frame-ancestors from the second policy")
+}
+
+func TestSearchSecurityHeaders(t *testing.T) {
+	s := newScanner()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app.js" {
+			w.Header().Set("Content-Type", "application/javascript")
+			fmt.Fprint(w, `const a = 1`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+		fmt.Fprint(w, `<html></html>`)
+	}))
+	defer mockServer.Close()
+	opts := Options{Secrets: true, SecurityHeaders: true, IgnoreRobots: true}
+	s.compiledSecretRegex = s.compileSecretRegex(opts)
+
+	//Test case: The headers of pages are checked
+	out, err := s.search(context.TODO(), mockServer.URL+"/", 0, opts, &URLQueue{})
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []Finding{{URL: mockServer.URL + "/", Type: securityHeaderFinding, Value: "Content-Security-Policy not set", Source: "headers", Details: "missing", Count: 1, Severity: "low"}}, out, "Expected the missing CSP")
+
+	//Test case: Each issue is only reported once for a host
+	out, err = s.search(context.TODO(), mockServer.URL+"/other", 0, opts, &URLQueue{})
+	assert.Nil(t, err, "Unexpected error")
+	assert.Empty(t, out, "Expected the same issue on the same host to be left out")
+
+	//Test case: Scripts aren't checked
+	s.reportedFindings = &sync.Map{}
+	out, err = s.search(context.TODO(), mockServer.URL+"/app.js", 0, opts, &URLQueue{})
+	assert.Nil(t, err, "Unexpected error")
+	assert.Empty(t, out, "Expected no findings for a script")
+}
@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The severities of findings, from lowest to highest
+var severityLevels = []string{"info", "low", "medium", "high", "critical"}
+
+// The severity of each type of finding before scoreFinding adjusts it. Secrets that give access to infrastructure or
+// money are critical, and things that are usually public by design, like Firebase URLs and Google API keys, are lower.
+var baseSeverity = map[string]string{
+	"AWS Access Key ID":                           "critical",
+	"RSA Private Key":                             "critical",
+	"DSA Private Key":                             "critical",
+	"EC Private Key":                              "critical",
+	"PGP Private Key":                             "critical",
+	"GCP Service Account Private Key":             "critical",
+	"Azure Storage Account Key":                   "critical",
+	"Stripe Standard API Key":                     "critical",
+	"Cloudinary URL":                              "critical",
+	"GitHub Personal Access Token (Classic)":      "high",
+	"GitHub Personal Access Token (Fine-Grained)": "high",
+	"GitHub OAuth 2.0 Access Token":               "high",
+	"GitHub User-to-Server Access Token":          "high",
+	"GitHub Server-to-Server Access Token":        "high",
+	"GitHub Refresh Token":                        "high",
+	"Google OAuth 2.0 Access Token":               "high",
+	"Facebook Access Token":                       "high",
+	"Twitter Access Token":                        "high",
+	"Heroku OAuth 2.0":                            "high",
+	"Amazon Marketing Services Auth Token":        "high",
+	"MailChimp":                                   "high",
+	"Stripe Restricted API Key":                   "high",
+	"Square Access Token":                         "high",
+	"Square OAuth Secret":                         "high",
+	"Paypal / Braintree Access Token":             "high",
+	"Slack OAuth v2 Bot Access Token":             "high",
+	"Slack OAuth v2 User Access Token":            "high",
+	"Slack OAuth v2 Configuration Token":          "high",
+	"Slack OAuth v2 Refresh Token":                "high",
+	"Mailgun API Key":                             "high",
+	"SendGrid API Key":                            "high",
+	"OpenAI API Key":                              "high",
+	"npm Access Token":                            "high",
+	"PyPI API Token":                              "high",
+	"Docker Hub Personal Access Token":            "high",
+	"Azure SAS Token":                             "high",
+	"Password in URL":                             "high",
+	configSecretFinding:                           "high",
+	"Slack Webhook":                               "medium",
+	"Slack Webhook URL":                           "medium",
+	"Facebook OAuth":                              "medium",
+	"Foursquare Secret Key":                       "medium",
+	"Picatic API Key":                             "medium",
+	"Generic API Key":                             "medium",
+	jwtFinding:                                    "medium",
+	"Google API Key":                              "low",
+	"Firebase URL":                                "low",
+	"Twilio Account SID":                          "low",
+	"Google Cloud Platform OAuth 2.0":             "low",
+	"High Entropy String":                         "low",
+	securityHeaderFinding:                         "low",
+	internalAddressFinding:                        "low",
+	stringFinding:                                 "info",
+	"URL":                                         "info",
+	"WebSocket URL":                               "info",
+	"EventSource URL":                             "info",
+	cssURLFinding:                                 "info",
+	xhrFinding:                                    "info",
+	fetchErrorFinding:                             "info",
+}
+
+// The severity of types that aren't in baseSeverity, like patterns loaded with the patterns flag
+const defaultSeverity = "medium"
+
+// The severity of the patterns from noisySecretRegex, since most of what they match isn't a secret
+const noisySeverity = "low"
+
+// severityRank gets the index of a severity in severityLevels, ignoring case
+//
+// Returns:
+//   - int: The index, higher is more severe.
+//   - bool: False if it isn't a severity.
+func severityRank(severity string) (int, bool) {
+	for i, level := range severityLevels {
+		if strings.EqualFold(severity, level) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// configureSeverity sets the lowest severity to report from the Options
+//
+// Returns:
+//   - error: If the MinSeverity isn't one of the severityLevels.
+func (s *scanner) configureSeverity(o Options) error {
+	s.minSeverity = 0
+	if o.MinSeverity == "" {
+		return nil
+	}
+	rank, ok := severityRank(o.MinSeverity)
+	if !ok {
+		return fmt.Errorf("min-severity must be info, low, medium, high or critical")
+	}
+	s.minSeverity = rank
+	return nil
+}
+
+// scoreFinding works out the severity of a finding from its type, then adjusts it by what's known about the value
+//
+// A secret that was verified as active is critical, and one that was rejected is at most low. Tokens that passed their
+// checksum are one level higher, since they're almost certainly real, and ones that failed it are info.
+//
+// Parameters:
+//   - finding: The finding, after it's been verified if the verify flag is used.
+//
+// Returns:
+//   - string: One of the severityLevels.
+func scoreFinding(finding Finding) string {
+	severity, ok := baseSeverity[finding.Type]
+	if !ok {
+		severity = defaultSeverity
+		_, noisy := noisySecretRegex[finding.Type]
+		_, strict := secretRegex[finding.Type]
+		if noisy && !strict {
+			severity = noisySeverity
+		}
+	}
+	rank, _ := severityRank(severity)
+
+	switch {
+	case finding.Verified == verifiedActive:
+		rank = len(severityLevels) - 1
+	case finding.Verified == verifiedInvalid:
+		if rank > 1 {
+			rank = 1
+		}
+	case finding.Details == invalidChecksum:
+		rank = 0
+	default:
+		if _, ok := checksumValidators[finding.Type]; ok && rank < len(severityLevels)-1 {
+			rank++
+		}
+	}
+	return severityLevels[rank]
+}
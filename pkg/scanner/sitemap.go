@@ -0,0 +1,206 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	netUrl "net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The most bytes of a sitemap to read after decompressing it, which is the limit from the sitemap protocol
+const maxSitemapSize = 50 * 1024 * 1024
+
+// The most sitemaps to fetch for each input URL, since sitemap index files can point to any number of sitemaps, or back
+// to themselves
+const maxSitemaps = 100
+
+// A sitemap is either a urlset with the pages of a site, or a sitemapindex that points to more sitemaps. They're parsed
+// into the same struct, since only the <loc> entries are needed.
+type sitemap struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// parseSitemap parses a sitemap or sitemap index file, which can be gzip compressed
+//
+// Parameters:
+//   - data: The contents of the sitemap file.
+//
+// Returns:
+//   - []string: The page URLs from a urlset.
+//   - []string: The sitemap URLs from a sitemap index.
+//   - error: If the file isn't valid XML or gzip.
+func parseSitemap(data []byte) ([]string, []string, error) {
+	//Checked by the magic bytes rather than the .gz extension or Content-Type, since servers aren't consistent with either
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer reader.Close()
+		data, err = io.ReadAll(io.LimitReader(reader, maxSitemapSize))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var parsed sitemap
+	err := xml.Unmarshal(data, &parsed)
+	if err != nil {
+		return nil, nil, err
+	}
+	var urls, sitemaps []string
+	for _, entry := range parsed.URLs {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			urls = append(urls, loc)
+		}
+	}
+	for _, entry := range parsed.Sitemaps {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+	return urls, sitemaps, nil
+}
+
+// fetchSitemap gets the contents of a sitemap file
+//
+// Parameters:
+//   - ctx: The context for the request, used to cancel it if needed.
+//   - url: The URL of the sitemap.
+//
+// Returns:
+//   - []byte: The contents of the sitemap, which can still be gzip compressed.
+//   - error: If the request fails or doesn't return a 200.
+func (s *scanner) fetchSitemap(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.addRequestHeaders(req, url)
+
+	res, err := s.httpClient.Do(req)
+	atomic.AddInt64(&s.requestsSent, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxSitemapSize))
+}
+
+// sitemapURLs gets the page URLs from the sitemap of a site, following any sitemap index files
+//
+// The sitemap is read from /sitemap.xml, or /sitemap.xml.gz if there isn't one. Sitemaps that can't be fetched or
+// parsed are skipped with a warning, since the input URL is still searched without them.
+//
+// Parameters:
+//   - ctx: The context for the requests, used to cancel them if needed.
+//   - url: The input URL, only its scheme and host are used.
+//   - limiter: The rate limiter for the run, each sitemap counts as a request.
+//
+// Returns:
+//   - []string: The page URLs from every sitemap found.
+func (s *scanner) sitemapURLs(ctx context.Context, url string, limiter *hostLimiter) []string {
+	parsedUrl, err := netUrl.Parse(addScheme(url))
+	if err != nil || parsedUrl.Host == "" {
+		return nil
+	}
+	origin := parsedUrl.Scheme + "://" + parsedUrl.Host
+
+	var urls []string
+	queue := []string{origin + "/sitemap.xml"}
+	seen := map[string]bool{}
+	for len(queue) > 0 && len(seen) < maxSitemaps {
+		sitemapUrl := queue[0]
+		queue = queue[1:]
+		if seen[sitemapUrl] {
+			continue
+		}
+		seen[sitemapUrl] = true
+
+		err := limiter.Wait(ctx, sitemapUrl)
+		if err != nil {
+			return urls
+		}
+		data, err := s.fetchSitemap(ctx, sitemapUrl)
+		//Only the first sitemap is a guess, so the compressed one is tried before warning about it
+		if err != nil && sitemapUrl == origin+"/sitemap.xml" {
+			queue = append(queue, origin+"/sitemap.xml.gz")
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning - Failed to get sitemap %s: %s\n", sitemapUrl, err)
+			continue
+		}
+		pages, sitemaps, err := parseSitemap(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning - Failed to parse sitemap %s: %s\n", sitemapUrl, err)
+			continue
+		}
+		urls = append(urls, pages...)
+		queue = append(queue, sitemaps...)
+	}
+	return urls
+}
+
+// sitemapPages gets the page URLs from the sitemaps of the sites of the input URLs for prepare
+//
+// Each site's sitemap is only fetched once, however many input URLs are on it, and the sites are fetched at the same
+// time.
+//
+// Parameters:
+//   - ctx: The context for the requests, used to cancel them if needed.
+//   - urls: The input URLs.
+//   - limiter: The rate limiter for the run, each sitemap counts as a request.
+//
+// Returns:
+//   - []string: The page URLs from the sitemap of each site, in the order of the input URLs.
+func (s *scanner) sitemapPages(ctx context.Context, urls []string, limiter *hostLimiter) []string {
+	var origins []string
+	seen := map[string]struct{}{}
+	for _, url := range urls {
+		parsedUrl, err := netUrl.Parse(addScheme(url))
+		if err != nil || parsedUrl.Host == "" {
+			continue
+		}
+		origin := parsedUrl.Scheme + "://" + parsedUrl.Host
+		if _, ok := seen[origin]; ok {
+			continue
+		}
+		seen[origin] = struct{}{}
+		origins = append(origins, origin)
+	}
+
+	pages := make([][]string, len(origins))
+	var wg sync.WaitGroup
+	for i, origin := range origins {
+		wg.Add(1)
+		go func(i int, origin string) {
+			defer wg.Done()
+			pages[i] = s.sitemapURLs(ctx, origin, limiter)
+		}(i, origin)
+	}
+	wg.Wait()
+
+	var urlsFound []string
+	for _, sitePages := range pages {
+		urlsFound = append(urlsFound, sitePages...)
+	}
+	return urlsFound
+}
@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gzipBytes compresses data like a sitemap.xml.gz file
+func gzipBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(data))
+	assert.Nil(t, err, "Unexpected error")
+	assert.Nil(t, writer.Close(), "Unexpected error")
+	return buf.Bytes()
+}
+
+func TestParseSitemap(t *testing.T) {
+	//Test case: Pages from a urlset
+	urlset := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc><lastmod>2024-01-01</lastmod></url>
+  <url><loc>
+    https://example.com/about
+  </loc></url>
+</urlset>`
+	urls, sitemaps, err := parseSitemap([]byte(urlset))
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/about"}, urls, "Unexpected URLs")
+	assert.Nil(t, sitemaps, "Expected no sitemaps in a urlset")
+
+	//Test case: Sitemaps from a sitemap index
+	index := `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-1.xml.gz</loc></sitemap>
+</sitemapindex>`
+	urls, sitemaps, err = parseSitemap([]byte(index))
+	assert.Nil(t, err, "Unexpected error")
+	assert.Nil(t, urls, "Expected no URLs in a sitemap index")
+	assert.Equal(t, []string{"https://example.com/sitemap-1.xml.gz"}, sitemaps, "Unexpected sitemaps")
+
+	//Test case: Gzip compressed sitemaps
+	urls, _, err = parseSitemap(gzipBytes(t, urlset))
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/about"}, urls, "Expected the compressed sitemap to be parsed")
+
+	//Test case: Invalid XML
+	_, _, err = parseSitemap([]byte("<urlset><url>"))
+	assert.NotNil(t, err, "Expected an error for invalid XML")
+}
+
+func TestSitemapURLs(t *testing.T) {
+	s := newScanner()
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%[1]s/pages.xml.gz</loc></sitemap><sitemap><loc>%[1]s/sitemap.xml</loc></sitemap><sitemap><loc>%[1]s/missing.xml</loc></sitemap></sitemapindex>`, mockServer.URL)
+		case "/pages.xml.gz":
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Write(gzipBytes(t, fmt.Sprintf(`<urlset><url><loc>%[1]s/</loc></url><url><loc>%[1]s/about</loc></url><url><loc>https://other.example.net/</loc></url></urlset>`, mockServer.URL)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	//Test case: Sitemap index files are followed, including compressed sitemaps, and loops are ignored
+	urls := s.sitemapURLs(context.Background(), mockServer.URL+"/some/page", newHostLimiter(0, 1))
+	assert.Equal(t, []string{mockServer.URL + "/", mockServer.URL + "/about", "https://other.example.net/"}, urls, "Unexpected URLs")
+
+	//Test case: The compressed sitemap is used if there isn't a sitemap.xml
+	gzServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sitemap.xml.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(gzipBytes(t, `<urlset><url><loc>https://example.com/</loc></url></urlset>`))
+	}))
+	defer gzServer.Close()
+	urls = s.sitemapURLs(context.Background(), gzServer.URL, newHostLimiter(0, 1))
+	assert.Equal(t, []string{"https://example.com/"}, urls, "Expected the URLs from sitemap.xml.gz")
+
+	//Test case: No sitemap
+	emptyServer := httptest.NewServer(http.NotFoundHandler())
+	defer emptyServer.Close()
+	urls = s.sitemapURLs(context.Background(), emptyServer.URL, newHostLimiter(0, 1))
+	assert.Nil(t, urls, "Expected no URLs without a sitemap")
+}
+
+func TestPrepareSitemap(t *testing.T) {
+	var mockServer *httptest.Server
+	var sitemapRequests int32
+	var sitemapHeader atomic.Value
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap.xml" {
+			atomic.AddInt32(&sitemapRequests, 1)
+			sitemapHeader.Store(r.Header.Get("X-Api-Key"))
+			fmt.Fprintf(w, `<urlset><url><loc>%[1]s/about</loc></url><url><loc>https://other.example.net/</loc></url></urlset>`, mockServer.URL)
+		}
+	}))
+	defer mockServer.Close()
+	opts := DefaultOptions()
+	opts.Rate = 0
+
+	//Test case: Without the sitemap flag, only the input URL is queued
+	_, urlQueue, err := testPrepare([]string{mockServer.URL}, opts)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{mockServer.URL + "/"}, urlQueue.queue, "Expected only the input URL")
+
+	//Test case: The pages in scope from the sitemap are queued after the input URL
+	opts.Sitemap = true
+	_, urlQueue, err = testPrepare([]string{mockServer.URL}, opts)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{mockServer.URL + "/", mockServer.URL + "/about"}, urlQueue.queue, "Expected the sitemap pages in scope")
+
+	//Test case: The sitemap of a site is only fetched once for every input URL on it, with the headers from the flags
+	atomic.StoreInt32(&sitemapRequests, 0)
+	opts.Headers = []string{"X-Api-Key: secret"}
+	_, urlQueue, err = testPrepare([]string{mockServer.URL, mockServer.URL + "/login"}, opts)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, []string{mockServer.URL + "/", mockServer.URL + "/login", mockServer.URL + "/about"}, urlQueue.queue, "Expected the sitemap pages once")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sitemapRequests), "Expected the sitemap to be fetched once")
+	assert.Equal(t, "secret", sitemapHeader.Load(), "Expected the header from the flags")
+}
@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	netUrl "net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Matches the sourceMappingURL comment that build tools add to the end of a script, with either //# or the older //@
+var sourceMappingPattern = regexp.MustCompile(`//[#@]\s*sourceMappingURL=(\S+)`)
+
+// A sourcemap is the part of a sourcemap file with the original source code in it
+type sourcemap struct {
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent"`
+}
+
+// isScriptURL checks if the path of a URL ends in .js, ignoring any query string or fragment
+func isScriptURL(url string) bool {
+	parsedUrl, err := netUrl.Parse(url)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(parsedUrl.Path), ".js")
+}
+
+// sourcemapURL finds the sourcemap for a script
+//
+// The last sourceMappingURL comment in the script is used if there is one, resolved against the script URL. Otherwise
+// the sourcemap is guessed by adding .map to the end of the script path.
+//
+// Parameters:
+//   - scriptUrl: The URL of the script.
+//   - script: The contents of the script.
+//
+// Returns:
+//   - string: The URL of the sourcemap, which can be a data: URL for inline sourcemaps.
+//   - error
+func sourcemapURL(scriptUrl string, script string) (string, error) {
+	matches := sourceMappingPattern.FindAllStringSubmatch(script, -1)
+	if len(matches) > 0 {
+		ref := matches[len(matches)-1][1]
+		if strings.HasPrefix(ref, "data:") {
+			return ref, nil
+		}
+		return resolveURL(scriptUrl, ref)
+	}
+
+	parsedUrl, err := netUrl.Parse(scriptUrl)
+	if err != nil {
+		return "", err
+	}
+	parsedUrl.Path += ".map"
+	parsedUrl.RawPath = ""
+	return parsedUrl.String(), nil
+}
+
+// decodeDataURL gets the contents of an inline sourcemap from a data: URL, which can be base64 or percent encoded
+func decodeDataURL(dataUrl string) (string, error) {
+	header, data, found := strings.Cut(strings.TrimPrefix(dataUrl, "data:"), ",")
+	if !found {
+		return "", fmt.Errorf("invalid data URL")
+	}
+	if strings.HasSuffix(header, ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", err
+		}
+		return string(decoded), nil
+	}
+	return netUrl.PathUnescape(data)
+}
+
+// getSourcemap gets the original source files for a script from its sourcemap
+//
+// Sourcemaps on hosts that are out of scope are skipped with a warning, the same as scripts.
+//
+// Parameters:
+//   - ctx: The context for the search, used to cancel the request if needed.
+//   - scriptUrl: The URL of the script.
+//   - script: The contents of the script.
+//
+// Returns:
+//   - []scriptInfo: The name and contents of each original source file in the sourcemap.
+//   - error
+func (s *scanner) getSourcemap(ctx context.Context, scriptUrl string, script string) ([]scriptInfo, error) {
+	mapUrl, err := sourcemapURL(scriptUrl, script)
+	if err != nil {
+		//Non-breaking error, the script itself is still searched
+		fmt.Fprintf(os.Stderr, "Warning - Skipping sourcemap of %s: %s\n", scriptUrl, err)
+		return nil, nil
+	}
+
+	var contents string
+	if strings.HasPrefix(mapUrl, "data:") {
+		contents, err = decodeDataURL(mapUrl)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning - Skipping inline sourcemap of %s: %s\n", scriptUrl, err)
+			return nil, nil
+		}
+	} else {
+		//A sourceMappingURL can point anywhere, and the request would carry the cookies and headers for the site
+		if !s.inScope(mapUrl) {
+			fmt.Fprintf(os.Stderr, "Warning - Skipping sourcemap %s, out of scope\n", mapUrl)
+			return nil, nil
+		}
+		//Read whatever the Content-Type, since servers often don't know the .map extension
+		res, err := s.getResponse(ctx, mapUrl, scriptUrl, nil)
+		var fetchErr *fetchError
+		if errors.As(err, &fetchErr) {
+			//Non-breaking error, like when there is no sourcemap
+			fmt.Fprintf(os.Stderr, "Warning - %s\n", err)
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		contents = res.Text
+	}
+
+	var parsed sourcemap
+	err = json.Unmarshal([]byte(contents), &parsed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning - Skipping sourcemap %s, it isn't valid JSON: %s\n", mapUrl, err)
+		return nil, nil
+	}
+
+	//Sourcemaps without sourcesContent only have the file names, the original code would need to be fetched separately
+	var sources []scriptInfo
+	for i, content := range parsed.SourcesContent {
+		if content == "" {
+			continue
+		}
+		source := scriptInfo{Content: content}
+		if i < len(parsed.Sources) {
+			source.Src = parsed.Sources[i]
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
@@ -0,0 +1,207 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// How often the state file is written while URLs are being searched, it's always written at the end of a run as well
+var stateInterval = 5 * time.Second
+
+// The contents of a state file
+type scanState struct {
+	Completed []string `json:"completed"`
+	//The URLs that were queued or being searched when the file was written, which are queued again on resume
+	Pending []pendingURL `json:"pending,omitempty"`
+}
+
+// A pendingURL is a URL in the state file that hasn't been searched yet, with the depth it was queued at
+type pendingURL struct {
+	URL   string `json:"url"`
+	Depth int    `json:"depth"`
+}
+
+// A stateTracker keeps the set of URLs that have been searched for the state flag, along with the ones still waiting
+// to be searched, and writes them to the state file
+type stateTracker struct {
+	mu        sync.Mutex
+	path      string
+	completed map[string]struct{}
+	//The pending URLs from the file, until they're queued again by resume
+	pending []pendingURL
+	//The queue of the run, and the URLs from it that are being searched with their depth
+	queue   *URLQueue
+	started map[string]int
+	//Whether URLs were started or completed since the file was last written, and when that was
+	dirty     bool
+	lastSaved time.Time
+}
+
+// loadState reads the URLs that were already searched from a state file
+//
+// Parameters:
+//   - path: The path to the state file, which doesn't need to exist yet.
+//
+// Returns:
+//   - *stateTracker: The tracker for the run, with the completed URLs from the file.
+//   - error: If the file exists but can't be read or parsed.
+func loadState(path string) (*stateTracker, error) {
+	tracker := &stateTracker{path: path, completed: map[string]struct{}{}, started: map[string]int{}, lastSaved: time.Now()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tracker, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state scanState
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, err
+	}
+	for _, url := range state.Completed {
+		tracker.completed[normalizeURL(url)] = struct{}{}
+	}
+	tracker.pending = state.Pending
+	return tracker, nil
+}
+
+// resume queues the pending URLs from the state file again at their depth, so the URLs found by the run being resumed
+// are still searched even though the pages they were found on are skipped. The queue is kept to write the URLs still in
+// it to the state file.
+func (s *stateTracker) resume(queue *URLQueue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, pending := range s.pending {
+		queue.PushAt(pending.URL, pending.Depth)
+	}
+	s.pending = nil
+	s.queue = queue
+}
+
+// start marks a URL from the queue as being searched, so it's still pending in the state file until it's completed
+func (s *stateTracker) start(url string, depth int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started[normalizeURL(url)] = depth
+	s.dirty = true
+}
+
+// isCompleted checks if a URL was searched by this run or the one being resumed
+func (s *stateTracker) isCompleted(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.completed[normalizeURL(url)]
+	return ok
+}
+
+// complete adds a URL that has been searched, and writes the state file if it hasn't been written for stateInterval
+func (s *stateTracker) complete(url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed[normalizeURL(url)] = struct{}{}
+	delete(s.started, normalizeURL(url))
+	s.dirty = true
+	if time.Since(s.lastSaved) < stateInterval {
+		return nil
+	}
+	return s.save()
+}
+
+// flush writes the state file if any URLs were started or completed since it was last written
+func (s *stateTracker) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save()
+}
+
+// save writes the state file with writeFileAtomic. s.mu must be held.
+func (s *stateTracker) save() error {
+	if !s.dirty {
+		return nil
+	}
+	state := scanState{Completed: make([]string, 0, len(s.completed))}
+	for url := range s.completed {
+		state.Completed = append(state.Completed, url)
+	}
+	sort.Strings(state.Completed)
+	state.Pending = s.pendingURLs()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(s.path, data)
+	if err != nil {
+		return err
+	}
+
+	s.dirty = false
+	s.lastSaved = time.Now()
+	return nil
+}
+
+// pendingURLs gets the URLs that are being searched or still in the queue, at the shallowest depth each one is at and
+// sorted by URL. s.mu must be held.
+func (s *stateTracker) pendingURLs() []pendingURL {
+	depths := map[string]int{}
+	add := func(url string, depth int) {
+		if _, ok := s.completed[url]; ok {
+			return
+		}
+		if seenDepth, ok := depths[url]; ok && seenDepth <= depth {
+			return
+		}
+		depths[url] = depth
+	}
+	for url, depth := range s.started {
+		add(url, depth)
+	}
+	//Anything left from the file that wasn't queued again yet
+	for _, pending := range s.pending {
+		add(normalizeURL(pending.URL), pending.Depth)
+	}
+	if s.queue != nil {
+		urls, urlDepths := s.queue.Queued()
+		for i, url := range urls {
+			add(url, urlDepths[i])
+		}
+	}
+
+	var pending []pendingURL
+	for url, depth := range depths {
+		pending = append(pending, pendingURL{URL: url, Depth: depth})
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].URL < pending[j].URL
+	})
+	return pending
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory as path and then renames it over path, so the
+// file is never left half written if webstrings is stopped in the middle
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	//Only does anything if the rename didn't happen
+	defer os.Remove(file.Name())
+	_, err = file.Write(append(data, '\n'))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
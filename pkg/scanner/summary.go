@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync/atomic"
+)
+
+// countSearched adds a URL to the summary counts, failed is true if it couldn't be fetched
+func (s *scanner) countSearched(failed bool) {
+	atomic.AddInt64(&s.urlsSearched, 1)
+	if failed {
+		atomic.AddInt64(&s.urlsFailed, 1)
+	}
+}
+
+// writeSummary writes how big the run was and how many findings there were of each type
+//
+// Parameters:
+//   - w: Where to write the summary, stderr so it doesn't end up in the output with the findings.
+//   - searched: The number of URLs searched.
+//   - failed: The number of those URLs that couldn't be fetched.
+//   - findings: Every finding from the run.
+//
+// Example:
+//
+//	Searched 12 URLs (1 failed), found 3 results
+//	  AWS Access Key ID: 1
+//	  GitHub Personal Access Token (Classic): 2
+func writeSummary(w io.Writer, searched int64, failed int64, findings []Finding) {
+	counts := map[string]int{}
+	var types []string
+	for _, finding := range findings {
+		if _, ok := counts[finding.Type]; !ok {
+			types = append(types, finding.Type)
+		}
+		counts[finding.Type]++
+	}
+	sort.Strings(types)
+
+	fmt.Fprintf(w, "\nSearched %d %s (%d failed), found %d %s\n", searched, plural(int(searched), "URL", "URLs"), failed, len(findings), plural(len(findings), "result", "results"))
+	for _, findingType := range types {
+		fmt.Fprintf(w, "  %s: %d\n", findingType, counts[findingType])
+	}
+}
+
+// plural picks the singular or plural form of a word for a count
+func plural(count int, singular string, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// The Transport of responses that weren't encrypted, which formatFinding reports on its own since that's an issue by itself
+const plainHTTPTransport = "plain HTTP"
+
+// Names for the TLS versions, since tls.VersionName needs a newer version of Go
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// describeTransport describes how a response was sent, for the Transport of findings in verify mode
+//
+// Parameters:
+//   - state: The TLS connection state of the response, nil if it was sent over plain HTTP.
+//
+// Returns:
+//   - string: The TLS version and whether the certificate chain is valid, like "TLS 1.3, valid certificate", or
+//     plainHTTPTransport.
+func describeTransport(state *tls.ConnectionState) string {
+	if state == nil {
+		return plainHTTPTransport
+	}
+	version, ok := tlsVersionNames[state.Version]
+	if !ok {
+		version = fmt.Sprintf("TLS 0x%04x", state.Version)
+	}
+	if certificateValid(state) {
+		return version + ", valid certificate"
+	}
+	return version + ", invalid certificate"
+}
+
+// certificateValid checks if the certificate chain of a TLS connection is valid for the server name
+//
+// The chain is already verified unless the insecure flag is used, in which case it's checked against the system roots
+// here, so the finding still says if it would have been trusted.
+func certificateValid(state *tls.ConnectionState) bool {
+	if len(state.VerifiedChains) > 0 {
+		return true
+	}
+	if len(state.PeerCertificates) == 0 {
+		return false
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{DNSName: state.ServerName, Intermediates: intermediates})
+	return err == nil
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeTransport(t *testing.T) {
+	s := newScanner()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Successful response")
+	})
+
+	//Test case: Plain HTTP
+	mockServer := httptest.NewServer(handler)
+	defer mockServer.Close()
+	res, err := s.getResponse(context.TODO(), mockServer.URL, mockServer.URL, nil)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, plainHTTPTransport, res.Transport, "Expected plain HTTP transport")
+
+	//Test case: Self-signed certificate with insecure is reported as invalid
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	client, err := newHTTPClient(time.Second, "", true)
+	assert.Nil(t, err, "Unexpected error")
+	s.httpClient = client
+	res, err = s.getResponse(context.TODO(), tlsServer.URL, tlsServer.URL, nil)
+	assert.Nil(t, err, "Unexpected error")
+	assert.Equal(t, "TLS 1.3, invalid certificate", res.Transport, "Unexpected transport")
+
+	//Test case: Unknown TLS version
+	state := &tls.ConnectionState{Version: 0x0300}
+	assert.Equal(t, "TLS 0x0300, invalid certificate", describeTransport(state), "Unexpected transport")
+}
@@ -0,0 +1,238 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The Decisions that can be made about a finding in the triage TUI
+const (
+	truePositive  = "true positive"
+	falsePositive = "false positive"
+)
+
+// How many lines the triage TUI uses for the help and the details of the selected finding, below the list
+const triageFooterHeight = 6
+
+// A triageModel is the bubbletea model for the triage TUI
+type triageModel struct {
+	//The findings being triaged, grouped by type
+	findings []Finding
+	cursor   int
+	//The index of the first finding shown, so the list scrolls with the cursor
+	offset int
+	height int
+}
+
+// newTriageModel creates the model for the triage TUI, with the findings sorted so each type is together
+func newTriageModel(findings []Finding) triageModel {
+	sorted := append([]Finding(nil), findings...)
+	//Stable so findings of the same type stay in the order they were found in
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Type < sorted[j].Type })
+	return triageModel{findings: sorted, height: 24}
+}
+
+func (m triageModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m triageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor--
+		case "down", "j":
+			m.cursor++
+		case "pgup":
+			m.cursor -= m.listHeight()
+		case "pgdown":
+			m.cursor += m.listHeight()
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = len(m.findings) - 1
+		case "t":
+			m.decide(truePositive)
+		case "f":
+			m.decide(falsePositive)
+		case "u", " ":
+			m.decide("")
+		}
+	}
+
+	if m.cursor >= len(m.findings) {
+		m.cursor = len(m.findings) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	//Scroll just far enough to keep the cursor on screen
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+	return m, nil
+}
+
+// decide sets the Decision of the selected finding and moves on to the next one
+func (m *triageModel) decide(decision string) {
+	if len(m.findings) == 0 {
+		return
+	}
+	m.findings[m.cursor].Decision = decision
+	if decision != "" {
+		m.cursor++
+	}
+}
+
+// listHeight is how many lines of the screen are left for the list of findings
+func (m triageModel) listHeight() int {
+	if m.height-triageFooterHeight < 1 {
+		return 1
+	}
+	return m.height - triageFooterHeight
+}
+
+func (m triageModel) View() string {
+	if len(m.findings) == 0 {
+		return "No results found, press q to quit\n"
+	}
+
+	var view strings.Builder
+	end := m.offset + m.listHeight()
+	if end > len(m.findings) {
+		end = len(m.findings)
+	}
+	for i := m.offset; i < end; i++ {
+		finding := m.findings[i]
+		//Each type starts with a header, including the type of the first finding on screen when scrolled past its header
+		if i == m.offset || finding.Type != m.findings[i-1].Type {
+			fmt.Fprintf(&view, "\x1b[1m%s\x1b[0m\n", finding.Type)
+		}
+		pointer := "  "
+		if i == m.cursor {
+			pointer = "> "
+		}
+		mark := "[ ]"
+		switch finding.Decision {
+		case truePositive:
+			mark = "[\x1b[32m✓\x1b[0m]"
+		case falsePositive:
+			mark = "[\x1b[31m✗\x1b[0m]"
+		}
+		fmt.Fprintf(&view, "%s%s %s\n", pointer, mark, finding.Value)
+	}
+
+	selected := m.findings[m.cursor]
+	fmt.Fprintf(&view, "\n\x1b[2mLocation: %s (%s)\x1b[0m\n", selected.URL, selected.Source)
+	if selected.Context != "" {
+		fmt.Fprintf(&view, "\x1b[2mContext: %q\x1b[0m\n", selected.Context)
+	}
+	fmt.Fprintf(&view, "\n%d/%d  t: true positive  f: false positive  u: undo  q: finish\n", m.cursor+1, len(m.findings))
+	return view.String()
+}
+
+// triageFindings shows the findings in the triage TUI so each can be marked as a true or false positive
+//
+// The TUI reads from the terminal rather than stdin, since the URLs might have been piped in, and is drawn on stderr so
+// that only the findings end up in the output. False positives are added to the ignore file so they aren't reported again.
+//
+// Parameters:
+//   - findings: The findings from the run, any fetch errors from the show-errors flag are kept without being triaged.
+//
+// Returns:
+//   - []Finding: The findings that weren't marked as false positives, with the Decision set for the true positives.
+//   - error
+func (s *scanner) triageFindings(findings []Finding) ([]Finding, error) {
+	var triage []Finding
+	var fetchErrors []Finding
+	for _, finding := range findings {
+		if finding.Type == fetchErrorFinding {
+			fetchErrors = append(fetchErrors, finding)
+		} else {
+			triage = append(triage, finding)
+		}
+	}
+
+	program := tea.NewProgram(newTriageModel(triage), tea.WithInputTTY(), tea.WithOutput(os.Stderr), tea.WithAltScreen())
+	final, err := program.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	kept, falsePositives := splitDecisions(final.(triageModel).findings)
+	if len(falsePositives) > 0 {
+		err = appendIgnoreFile(s.ignoreFilePath, falsePositives)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Added %d false %s to %s\n", len(falsePositives), plural(len(falsePositives), "positive", "positives"), s.ignoreFilePath)
+	}
+	return append(kept, fetchErrors...), nil
+}
+
+// splitDecisions separates the false positives from the rest of the triaged findings
+func splitDecisions(findings []Finding) ([]Finding, []Finding) {
+	var kept []Finding
+	var falsePositives []Finding
+	for _, finding := range findings {
+		if finding.Decision == falsePositive {
+			falsePositives = append(falsePositives, finding)
+		} else {
+			kept = append(kept, finding)
+		}
+	}
+	return kept, falsePositives
+}
+
+// appendIgnoreFile adds findings to an ignore file, creating it if it doesn't exist
+//
+// The SHA-256 hash of each value is written rather than the value itself, so real secrets aren't committed by accident,
+// with a comment above it saying what the finding was.
+func appendIgnoreFile(path string, findings []Finding) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	//A file edited by hand might not end with a newline, which would put the first comment on the end of its last line
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		_, err = file.ReadAt(last, info.Size()-1)
+		if err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			_, err = file.WriteString("\n")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, finding := range findings {
+		hash := sha256.Sum256([]byte(finding.Value))
+		_, err = fmt.Fprintf(file, "# %s found in %s\nsha256:%s\n", finding.Type, finding.URL, hex.EncodeToString(hash[:]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
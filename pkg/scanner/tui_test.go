@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// pressKeys sends key presses to the triage TUI, like the terminal would
+func pressKeys(model triageModel, keys ...string) triageModel {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updated, _ := model.Update(msg)
+		model = updated.(triageModel)
+	}
+	return model
+}
+
+func TestTriageModel(t *testing.T) {
+	findings := []Finding{
+		{URL: "https://example.com/app.js", Type: "GitHub Personal Access Token (Classic)", Value: "ghp_1", Source: "response"},
+		{URL: "https://example.com/app.js", Type: "AWS Access Key ID", Value: "AKIA1", Source: "response"},
+		{URL: "https://example.com/vendor.js", Type: "GitHub Personal Access Token (Classic)", Value: "ghp_2", Source: "response"},
+	}
+
+	//Test case: Findings are grouped by type, keeping the order they were found in
+	model := newTriageModel(findings)
+	values := []string{}
+	for _, finding := range model.findings {
+		values = append(values, finding.Value)
+	}
+	assert.Equal(t, []string{"AKIA1", "ghp_1", "ghp_2"}, values, "Unexpected order")
+	view := model.View()
+	assert.Equal(t, 1, strings.Count(view, "GitHub Personal Access Token (Classic)"), "Expected one header for each type")
+	assert.Contains(t, view, "Location: https://example.com/app.js (response)", "Expected the details of the selected finding")
+
+	//Test case: Marking a finding moves on to the next one, and can be undone
+	model = pressKeys(model, "t", "f", "f", "up", "u")
+	decisions := []string{}
+	for _, finding := range model.findings {
+		decisions = append(decisions, finding.Decision)
+	}
+	assert.Equal(t, []string{truePositive, "", falsePositive}, decisions, "Unexpected decisions")
+	assert.Equal(t, 1, model.cursor, "Expected undoing not to move the cursor")
+
+	//Test case: True positives are marked in green and false positives in red
+	view = model.View()
+	assert.Contains(t, view, "[\x1b[32m✓\x1b[0m] AKIA1", "Expected a green mark for the true positive")
+	assert.Contains(t, view, "[\x1b[31m✗\x1b[0m] ghp_2", "Expected a red mark for the false positive")
+
+	//Test case: The cursor stays on the list
+	model = pressKeys(model, "down", "down", "G")
+	assert.Equal(t, 2, model.cursor, "Expected the cursor to stop at the end")
+	model = pressKeys(model, "g", "up")
+	assert.Equal(t, 0, model.cursor, "Expected the cursor to stop at the start")
+
+	//Test case: The list scrolls with the cursor
+	model.height = triageFooterHeight + 1
+	model = pressKeys(model, "down", "down")
+	assert.Equal(t, 2, model.offset, "Expected the list to scroll to the cursor")
+	assert.Contains(t, model.View(), "GitHub Personal Access Token (Classic)", "Expected the header of the first finding on screen")
+}
+
+func TestSplitDecisions(t *testing.T) {
+	findings := []Finding{
+		{Type: "AWS Access Key ID", Value: "AKIA1", Decision: truePositive},
+		{Type: "AWS Access Key ID", Value: "AKIA2", Decision: falsePositive},
+		{Type: "AWS Access Key ID", Value: "AKIA3"},
+	}
+	kept, falsePositives := splitDecisions(findings)
+	assert.Equal(t, []Finding{findings[0], findings[2]}, kept, "Expected the true positives and undecided findings to be kept")
+	assert.Equal(t, []Finding{findings[1]}, falsePositives, "Unexpected false positives")
+}
+
+func TestAppendIgnoreFile(t *testing.T) {
+	s := newScanner()
+	path := filepath.Join(t.TempDir(), ".webstringsignore")
+	falsePositives := []Finding{{URL: "https://example.com/app.js", Type: "AWS Access Key ID", Value: "AKIA0123456789ABCDEF"}}
+
+	//Test case: False positives are ignored on the next run, even when added to more than once
+	assert.Nil(t, appendIgnoreFile(path, falsePositives), "Unexpected error")
+	assert.Nil(t, appendIgnoreFile(path, []Finding{{Type: "String", Value: "example"}}), "Unexpected error")
+	ignored, err := loadIgnoreFile(path)
+	assert.Nil(t, err, "Unexpected error")
+	s.ignoredFindings = ignored
+	assert.True(t, s.isIgnored(falsePositives[0]), "Expected the false positive to be ignored")
+	assert.True(t, s.isIgnored(Finding{Type: "String", Value: "example"}), "Expected the second false positive to be ignored")
+	assert.False(t, s.isIgnored(Finding{Type: "AWS Access Key ID", Value: "AKIA1111111111111111"}), "Expected other findings to be reported")
+
+	//Test case: A newline is added to a file that doesn't end with one
+	path = filepath.Join(t.TempDir(), ".webstringsignore")
+	assert.Nil(t, os.WriteFile(path, []byte("example"), 0644), "Unexpected error")
+	assert.Nil(t, appendIgnoreFile(path, falsePositives), "Unexpected error")
+	ignored, err = loadIgnoreFile(path)
+	assert.Nil(t, err, "Unexpected error")
+	s.ignoredFindings = ignored
+	assert.True(t, s.isIgnored(Finding{Type: "String", Value: "example"}), "Expected the last line to be kept")
+	assert.True(t, s.isIgnored(falsePositives[0]), "Expected the false positive to be ignored")
+}
@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Base URLs of the provider APIs used to verify secrets, variables so the tests can point them at a mock server
+var (
+	githubAPI  = "https://api.github.com"
+	stripeAPI  = "https://api.stripe.com"
+	slackAPI   = "https://slack.com/api"
+	mailgunAPI = "https://api.mailgun.net"
+)
+
+// The statuses verifySecret reports for secrets the provider accepted and rejected
+const (
+	verifiedActive  = "verified active"
+	verifiedInvalid = "invalid/revoked"
+)
+
+// A verifier makes an authenticated request to a provider to check whether a secret is active
+//
+// It returns true if the provider accepted the secret, false if it rejected it, and an error if the response
+// didn't make it clear either way.
+type verifier func(s *scanner, ctx context.Context, secret string) (bool, error)
+
+// The secret descriptions from secretRegex that can be verified, and the verifier to use for each
+var verifiers = map[string]verifier{
+	"GitHub Personal Access Token (Classic)":      (*scanner).verifyGitHub,
+	"GitHub Personal Access Token (Fine-Grained)": (*scanner).verifyGitHub,
+	"GitHub OAuth 2.0 Access Token":               (*scanner).verifyGitHub,
+	"GitHub User-to-Server Access Token":          (*scanner).verifyGitHub,
+	"GitHub Server-to-Server Access Token":        (*scanner).verifyGitHub,
+	"Stripe Standard API Key":                     (*scanner).verifyStripe,
+	"Stripe Restricted API Key":                   (*scanner).verifyStripe,
+	"Slack OAuth v2 Bot Access Token":             (*scanner).verifySlack,
+	"Slack OAuth v2 User Access Token":            (*scanner).verifySlack,
+	"Mailgun API Key":                             (*scanner).verifyMailgun,
+}
+
+// verifySecret checks a secret against its provider's API, if there is a verifier for that type of secret
+//
+// Parameters:
+//   - ctx: The context for the search, used to cancel the verification request if needed.
+//   - description: The secret description from secretRegex that the secret matched.
+//   - secret: The secret that was found.
+//
+// Returns:
+//   - bool: True if the provider accepted the secret.
+//   - string: The status to report with the finding, like "verified active" or "invalid/revoked".
+func (s *scanner) verifySecret(ctx context.Context, description string, secret string) (bool, string) {
+	verify, ok := verifiers[description]
+	if !ok {
+		return false, "verification not supported"
+	}
+
+	active, err := verify(s, ctx, secret)
+	if err != nil {
+		return false, "verification failed: " + err.Error()
+	}
+	if active {
+		return true, verifiedActive
+	}
+	return false, verifiedInvalid
+}
+
+// checkStatus sends a verification request and turns the status code into a verifier result
+//
+// 200 means the secret is active, 401 and 403 mean it was rejected, and anything else is returned as an error.
+func (s *scanner) checkStatus(req *http.Request) (bool, error) {
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code: %s", res.Status)
+	}
+}
+
+func (s *scanner) verifyGitHub(ctx context.Context, secret string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", githubAPI+"/user", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "token "+secret)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	return s.checkStatus(req)
+}
+
+func (s *scanner) verifyStripe(ctx context.Context, secret string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", stripeAPI+"/v1/balance", nil)
+	if err != nil {
+		return false, err
+	}
+	req.SetBasicAuth(secret, "")
+	return s.checkStatus(req)
+}
+
+func (s *scanner) verifyMailgun(ctx context.Context, secret string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", mailgunAPI+"/v3/domains", nil)
+	if err != nil {
+		return false, err
+	}
+	req.SetBasicAuth("api", secret)
+	return s.checkStatus(req)
+}
+
+// Slack always responds with a 200, so the ok field of the response body is used instead of the status code
+func (s *scanner) verifySlack(ctx context.Context, secret string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", slackAPI+"/auth.test", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+secret)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	if err != nil {
+		return false, err
+	}
+	if body.Ok {
+		return true, nil
+	}
+	switch body.Error {
+	case "invalid_auth", "not_authed", "token_revoked", "token_expired", "account_inactive":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected error: %s", body.Error)
+	}
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"strings"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// The Type used to report the XHR and fetch requests a page makes in a DOM search with the urls flag
+const xhrFinding = "XHR/Fetch Endpoint"
+
+// An xhrRequest is an XHR or fetch request made by a page while it loaded
+type xhrRequest struct {
+	Method string
+	URL    string
+}
+
+// addRequest keeps a request from the page if it was an XHR or fetch request. n.mu must be held.
+//
+// Each method and URL is only kept once, since single page apps often poll the same endpoint. Inline data: and blob:
+// URLs don't point anywhere, so they are skipped.
+func (n *networkTracker) addRequest(event *network.EventRequestWillBeSent) {
+	if event.Request == nil || (event.Type != network.ResourceTypeXHR && event.Type != network.ResourceTypeFetch) {
+		return
+	}
+	url := event.Request.URL
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return
+	}
+	request := xhrRequest{Method: strings.ToUpper(event.Request.Method), URL: url}
+	if _, ok := n.seen[request]; ok {
+		return
+	}
+	n.seen[request] = struct{}{}
+	n.requests = append(n.requests, request)
+}
+
+// endpoints gets the XHR and fetch requests the page has made so far, in the order they were sent
+func (n *networkTracker) endpoints() []xhrRequest {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]xhrRequest(nil), n.requests...)
+}